@@ -0,0 +1,124 @@
+package gosql
+
+import (
+	"database/sql"
+)
+
+// Sum returns the sum of the column, 0 if there are no rows
+// gosql.Model(&Moments{}).Where("user_id = ?", 5).Sum("like_total")
+func (b *Builder) Sum(column string) (float64, error) {
+	return b.aggregate("SUM", column)
+}
+
+// Avg returns the average of the column, 0 if there are no rows,
+// the grouped rows return the average of the rows of the groups kept by Having
+func (b *Builder) Avg(column string) (float64, error) {
+	return b.aggregate("AVG", column)
+}
+
+// Max scans the maximum value of the column into dest, dest is NULL if there are no rows,
+// so use a nullable type such as sql.NullTime for the columns that can be empty
+// var last sql.NullTime
+// gosql.Model(&Moments{}).Where("user_id = ?", 5).Max("created_at", &last)
+func (b *Builder) Max(column string, dest interface{}) error {
+	return b.aggregateTo("MAX", column, dest)
+}
+
+// Min scans the minimum value of the column into dest, see Max
+func (b *Builder) Min(column string, dest interface{}) error {
+	return b.aggregateTo("MIN", column, dest)
+}
+
+func (b *Builder) aggregate(fn, column string) (float64, error) {
+	var value sql.NullFloat64
+	err := b.aggregateTo(fn, column, &value)
+	return value.Float64, err
+}
+
+// aggregateTo where condition is generated by the model like Count
+func (b *Builder) aggregateTo(fn, column string, dest interface{}) error {
+	b.initModel()
+
+	m := zeroValueFilter(b.reflectModel(nil), nil)
+	b.generateWhere(m)
+	b.softDeleteScope()
+
+	return b.db.GetContext(b.context(), dest, b.aggregateString(fn, column), b.queryArgs()...)
+}
+
+// Pluck selects the column of the rows to dest, dest is the pointer of slice
+// var names []string
+// gosql.Model(&Users{}).Where("status = 1").Pluck("name", &names)
+func (b *Builder) Pluck(column string, dest interface{}) error {
+	b.initModel()
+
+	m := zeroValueFilter(b.reflectModel(nil), nil)
+	b.generateWhere(m)
+	b.softDeleteScope()
+
+	return b.db.SelectContext(b.context(), dest, b.pluckString(column), b.queryArgs()...)
+}
+
+// Exists reports whether there is a row matching the conditions
+// gosql.Model(&Users{Name: "test"}).Exists()
+func (b *Builder) Exists() (bool, error) {
+	b.initModel()
+
+	m := zeroValueFilter(b.reflectModel(nil), nil)
+	b.generateWhere(m)
+	b.softDeleteScope()
+
+	return exists(b.db.GetContext(b.context(), new(int), b.existsString(), b.queryArgs()...))
+}
+
+// Sum returns the sum of the column, 0 if there are no rows
+// gosql.Table("moments").Where("user_id = ?", 5).Sum("like_total")
+func (m *Mapper) Sum(column string) (float64, error) {
+	return m.aggregate("SUM", column)
+}
+
+// Avg returns the average of the column, 0 if there are no rows,
+// the grouped rows return the average of the rows of the groups kept by Having
+func (m *Mapper) Avg(column string) (float64, error) {
+	return m.aggregate("AVG", column)
+}
+
+// Max scans the maximum value of the column into dest, see Builder.Max
+func (m *Mapper) Max(column string, dest interface{}) error {
+	return m.aggregateTo("MAX", column, dest)
+}
+
+// Min scans the minimum value of the column into dest, see Builder.Max
+func (m *Mapper) Min(column string, dest interface{}) error {
+	return m.aggregateTo("MIN", column, dest)
+}
+
+func (m *Mapper) aggregate(fn, column string) (float64, error) {
+	var value sql.NullFloat64
+	err := m.aggregateTo(fn, column, &value)
+	return value.Float64, err
+}
+
+func (m *Mapper) aggregateTo(fn, column string, dest interface{}) error {
+	return m.db.GetContext(m.context(), dest, m.aggregateString(fn, column), m.queryArgs()...)
+}
+
+// Pluck selects the column of the rows to dest, dest is the pointer of slice
+// var ids []int
+// gosql.Table("users").Where("status = 1").Pluck("id", &ids)
+func (m *Mapper) Pluck(column string, dest interface{}) error {
+	return m.db.SelectContext(m.context(), dest, m.pluckString(column), m.queryArgs()...)
+}
+
+// Exists reports whether there is a row matching the conditions
+func (m *Mapper) Exists() (bool, error) {
+	return exists(m.db.GetContext(m.context(), new(int), m.existsString(), m.queryArgs()...))
+}
+
+// exists the query of Exists returns sql.ErrNoRows if there is no row
+func exists(err error) (bool, error) {
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,119 @@
+package gosql
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/ilibs/gosql/v2/internal/example/models"
+)
+
+func TestBuilder_Aggregate(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		for i := 1; i <= 3; i++ {
+			insert(i)
+		}
+		insertStatus(4, 2)
+
+		tests := []struct {
+			name string
+			fn   func(column string) (float64, error)
+			want float64
+		}{
+			{"sum", Model(&models.Users{}).Where("status = 1").Sum, 6},
+			{"avg", Model(&models.Users{}).Where("status = 1").Avg, 2},
+			{"mapper sum", Table("users").Where("id > ?", 1).Sum, 9},
+			{"grouped sum", Table("users").GroupBy("status").Having("count(*) > 1").Sum, 6},
+			// the average of the rows, not the average of the group averages 2 and 4
+			{"grouped avg", Table("users").GroupBy("status").Avg, 2.5},
+			{"empty sum", Model(&models.Users{}).Where("status = 3").Sum, 0},
+		}
+
+		for _, tt := range tests {
+			got, err := tt.fn("id")
+			if err != nil {
+				t.Fatal(tt.name, err)
+			}
+			if got != tt.want {
+				t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+			}
+		}
+
+		var max, min int
+		if err := Model(&models.Users{}).Where("status = 1").Max("id", &max); err != nil || max != 3 {
+			t.Error("max error", max, err)
+		}
+		if err := Table("users").GroupBy("status").Min("id", &min); err != nil || min != 1 {
+			t.Error("grouped min error", min, err)
+		}
+
+		var name string
+		if err := Table("users").Max("name", &name); err != nil || name != "test4" {
+			t.Error("max of string column error", name, err)
+		}
+
+		var last sql.NullTime
+		if err := Model(&models.Users{}).Max("created_at", &last); err != nil || !last.Valid {
+			t.Error("max of datetime column error", last, err)
+		}
+
+		var empty sql.NullTime
+		if err := Model(&models.Users{}).Where("status = 3").Min("created_at", &empty); err != nil || empty.Valid {
+			t.Error("min of no rows must be NULL", empty, err)
+		}
+	})
+}
+
+func TestBuilder_Pluck(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		for i := 1; i <= 3; i++ {
+			insert(i)
+		}
+
+		var names []string
+		if err := Model(&models.Users{}).Where("id > ?", 1).OrderBy("id").Pluck("name", &names); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(names, []string{"test2", "test3"}) {
+			t.Error("pluck error", names)
+		}
+
+		var ids []int
+		if err := Table("users").OrderBy("id desc").Limit(2).Pluck("id", &ids); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(ids, []int{3, 2}) {
+			t.Error("mapper pluck error", ids)
+		}
+
+		if _, err := Model(&models.Users{Id: 4, Name: "test3", Status: 1}).Create(); err != nil {
+			t.Fatal(err)
+		}
+
+		names = nil
+		if err := Model(&models.Users{}).Distinct().Where("id > ?", 2).Pluck("name", &names); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(names, []string{"test3"}) {
+			t.Error("distinct pluck error", names)
+		}
+	})
+}
+
+func TestBuilder_Exists(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		insert(1)
+
+		if ok, err := Model(&models.Users{Name: "test1"}).Exists(); err != nil || !ok {
+			t.Error("exists error", ok, err)
+		}
+
+		if ok, err := Model(&models.Users{Name: "test2"}).Exists(); err != nil || ok {
+			t.Error("not exists error", ok, err)
+		}
+
+		if ok, err := Table("users").Where("id = ?", 1).Exists(); err != nil || !ok {
+			t.Error("mapper exists error", ok, err)
+		}
+	})
+}
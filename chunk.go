@@ -0,0 +1,178 @@
+package gosql
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Chunk selects the rows in batches of size ordered by the primary key and calls fn with the model slice after each batch,
+// the batch is walked by primary key instead of OFFSET, so the rows are not skipped or repeated if the table is changed,
+// it returns an error with Offset and stops when fn returns an error, for example:
+//
+//	var users []*Users
+//	gosql.Model(&users).Where("status = 1").Chunk(1000, func(batch interface{}) error {
+//	    for _, user := range users {
+//	        ...
+//	    }
+//	    return nil
+//	})
+func (b *Builder) Chunk(size int, fn func(batch interface{}) error) error {
+	if size < 1 {
+		return fmt.Errorf("chunk size must be greater than 0, but get %d", size)
+	}
+
+	b.initModel()
+	if err := b.lockError(); err != nil {
+		return err
+	}
+	// the offset would skip the rows after the primary key of every batch
+	if b.offset != "" {
+		return errors.New("chunk can not be used with offset, the batches are walked by primary key")
+	}
+	b.softDeleteScope()
+
+	rows := reflect.Indirect(reflect.ValueOf(b.model))
+	if rows.Kind() == reflect.Interface {
+		rows = reflect.Indirect(rows.Elem())
+	}
+	if rows.Kind() != reflect.Slice || !rows.CanSet() {
+		return fmt.Errorf("chunk model must be a pointer of slice, but get %T", b.model)
+	}
+
+	pk := b.modelEntity.PK()
+	column := b.qualifiedColumn(pk)
+
+	where, args := b.where, b.args
+	b.OrderBy(column).Limit(size)
+
+	var last interface{}
+	for {
+		b.where, b.args = where, append([]interface{}{}, args...)
+		if last != nil {
+			b.scope(column+" > ?", last)
+		}
+
+		// the rows of the last batch may be kept by fn, so the slice is not reused
+		rows.Set(reflect.MakeSlice(rows.Type(), 0, size))
+
+		var err error
+		if b.modelWrapper != nil {
+			err = b.db.SelectContext(b.context(), b.modelWrapper, b.queryString(), b.queryArgs()...)
+		} else {
+			err = b.db.SelectContext(b.context(), b.model, b.queryString(), b.queryArgs()...)
+		}
+		if err != nil {
+			return err
+		}
+
+		n := rows.Len()
+		if n == 0 {
+			return nil
+		}
+
+		hook := NewHook(b.ctx, b.db)
+		for i := 0; i < n; i++ {
+			hook.callMethod("AfterFind", rows.Index(i))
+		}
+		if hook.HasError() {
+			return hook.Error()
+		}
+		snapshot(b.model)
+
+		if err := fn(b.model); err != nil {
+			return err
+		}
+
+		if n < size {
+			return nil
+		}
+
+		field, ok := mapper.FieldMap(rows.Index(n - 1))[pk]
+		if !ok {
+			return fmt.Errorf("primary key %s is not a field of the model", pk)
+		}
+		last = reflect.Indirect(field).Interface()
+	}
+}
+
+// eachBatchSize is the number of rows read by Each before the relations of them are loaded
+const eachBatchSize = 100
+
+// Each streams the rows by sqlx.Rows and calls fn with the pointer of each row,
+// the AfterFind hooks and relations are applied to each row, it stops when fn returns an error, for example:
+//
+//	gosql.Model(&Users{}).Where("status = 1").Each(func(row interface{}) error {
+//	    user := row.(*Users)
+//	    ...
+//	    return nil
+//	})
+//
+// the relations are loaded once for every eachBatchSize rows while the rows are still read,
+// so Each with relations returns an error in a transaction, use Chunk instead
+func (b *Builder) Each(fn func(row interface{}) error) error {
+	b.initModel()
+	if err := b.lockError(); err != nil {
+		return err
+	}
+
+	t := reflect.Indirect(b.modelReflectValue).Type()
+	if b.db.tx != nil && hasRelation(t) {
+		return errors.New("each can not load the relations in a transaction, use Chunk instead")
+	}
+
+	b.softDeleteScope()
+	if err := b.cursorScope(); err != nil {
+		return err
+	}
+
+	rows, err := b.db.QueryxContext(b.context(), b.queryString(), b.queryArgs()...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	batch := reflect.MakeSlice(reflect.SliceOf(reflect.PtrTo(t)), 0, eachBatchSize)
+	for {
+		batch = batch.Slice(0, 0)
+		for batch.Len() < eachBatchSize && rows.Next() {
+			row := reflect.New(t)
+			if err := rows.StructScan(row.Interface()); err != nil {
+				return err
+			}
+			batch = reflect.Append(batch, row)
+		}
+
+		if batch.Len() == 0 {
+			return rows.Err()
+		}
+
+		if err := RelationAllContext(b.context(), b.modelWrapper, b.db, batch.Interface()); err != nil {
+			return err
+		}
+
+		for i := 0; i < batch.Len(); i++ {
+			row := batch.Index(i)
+			hook := NewHook(b.ctx, b.db)
+			hook.callMethod("AfterFind", row)
+			if hook.HasError() {
+				return hook.Error()
+			}
+			snapshot(row.Interface())
+
+			if err := fn(row.Interface()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// hasRelation reports whether the struct has a field with the relation tag
+func hasRelation(t reflect.Type) bool {
+	found := false
+	_ = eachField(t, func(field reflect.StructField, val string, name string, relations []string, connection string) error {
+		found = true
+		return nil
+	})
+	return found
+}
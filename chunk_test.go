@@ -0,0 +1,169 @@
+package gosql
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBuilder_Chunk(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		initDatas(t)
+
+		total, err := Model(&MomentList{}).Where("status = 1").Count()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var moments []*MomentList
+		var ids []int
+		batches := 0
+		err = Model(&moments).Where("status = 1").Chunk(4, func(batch interface{}) error {
+			batches++
+			if len(moments) > 4 {
+				t.Error("batch size error", len(moments))
+			}
+			for _, m := range moments {
+				if m.User == nil {
+					t.Error("relation must be loaded")
+				}
+				ids = append(ids, m.Id)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if int64(len(ids)) != total || batches != int((total+3)/4) {
+			t.Error("chunk must select every row once", len(ids), batches, total)
+		}
+
+		for i := 1; i < len(ids); i++ {
+			if ids[i] <= ids[i-1] {
+				t.Error("chunk order error", ids)
+				break
+			}
+		}
+
+		stop := errors.New("stop")
+		batches = 0
+		err = Model(&moments).Chunk(2, func(batch interface{}) error {
+			batches++
+			return stop
+		})
+		if err != stop || batches != 1 {
+			t.Error("chunk must stop when fn returns an error", err, batches)
+		}
+
+		if err := Model(&moments).Offset(2).Chunk(2, func(batch interface{}) error { return nil }); err == nil {
+			t.Error("chunk must not be used with offset")
+		}
+	})
+}
+
+func TestBuilder_ChunkTracker(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		if _, err := Model(&trackedPost{Title: "test"}).Create(); err != nil {
+			t.Fatal(err)
+		}
+
+		var posts []*trackedPost
+		err := Model(&posts).Chunk(10, func(batch interface{}) error {
+			for _, post := range posts {
+				if Changes(post) == nil {
+					t.Error("chunk must snapshot the tracked model")
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = Model(&trackedPost{}).Each(func(row interface{}) error {
+			if Changes(row) == nil {
+				t.Error("each must snapshot the tracked model")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestBuilder_Each(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		for i := 1; i <= 3; i++ {
+			insert(i)
+		}
+
+		num := 0
+		err := Model(&hookUser{}).Where("status = 1").OrderBy("id").Each(func(row interface{}) error {
+			num++
+			if user := row.(*hookUser); user.Name != "AfterUserName" {
+				t.Error("AfterFind must be called", user.Name)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if num != 3 {
+			t.Error("each rows error", num)
+		}
+
+		stop := errors.New("stop")
+		num = 0
+		err = Model(&hookUser{}).Each(func(row interface{}) error {
+			num++
+			return stop
+		})
+		if err != stop || num != 1 {
+			t.Error("each must stop when fn returns an error", err, num)
+		}
+
+		err = Tx(func(tx *DB) error {
+			return tx.Model(&MomentList{}).Each(func(row interface{}) error {
+				return nil
+			})
+		})
+		if err == nil {
+			t.Error("each must not load the relations in a transaction")
+		}
+	})
+}
+
+func TestBuilder_EachRelation(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		initDatas(t)
+
+		num := 0
+		err := Model(&MomentList{}).Where("status = 1").Each(func(row interface{}) error {
+			num++
+			if moment := row.(*MomentList); moment.Photos == nil {
+				t.Error("the relations must be loaded", moment.Id)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if num == 0 {
+			t.Error("each rows error")
+		}
+	})
+}
+
+func Test_hasRelation(t *testing.T) {
+	if !hasRelation(reflect.TypeOf(MomentList{})) {
+		t.Error("MomentList has relations")
+	}
+
+	if hasRelation(reflect.TypeOf(hookUser{})) {
+		t.Error("hookUser has no relations")
+	}
+}
@@ -0,0 +1,219 @@
+package gosql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Condition is a structured where condition, it can be used by Where and OrWhere, for example:
+//
+//	gosql.Model(&users).Where(gosql.Or(gosql.Eq("status", 1), gosql.In("id", []int{1, 2}))).All()
+type Condition interface {
+	// Build returns the condition SQL and args, the bind vars are written as ? and rebound by the builder
+	Build(d Dialect) (string, []interface{})
+}
+
+type compareCond struct {
+	column string
+	op     string
+	value  interface{}
+}
+
+func (c *compareCond) Build(d Dialect) (string, []interface{}) {
+	if e, ok := c.value.(*expr); ok {
+		return fmt.Sprintf("%s %s %s", quoteColumn(d, c.column), c.op, e.expr), e.args
+	}
+	return fmt.Sprintf("%s %s ?", quoteColumn(d, c.column), c.op), []interface{}{c.value}
+}
+
+// Eq column = value, a nil value is column IS NULL
+func Eq(column string, value interface{}) Condition {
+	if value == nil {
+		return IsNull(column)
+	}
+	return &compareCond{column: column, op: "=", value: value}
+}
+
+// Neq column <> value, a nil value is column IS NOT NULL
+func Neq(column string, value interface{}) Condition {
+	if value == nil {
+		return IsNotNull(column)
+	}
+	return &compareCond{column: column, op: "<>", value: value}
+}
+
+// Gt column > value
+func Gt(column string, value interface{}) Condition {
+	return &compareCond{column: column, op: ">", value: value}
+}
+
+// Gte column >= value
+func Gte(column string, value interface{}) Condition {
+	return &compareCond{column: column, op: ">=", value: value}
+}
+
+// Lt column < value
+func Lt(column string, value interface{}) Condition {
+	return &compareCond{column: column, op: "<", value: value}
+}
+
+// Lte column <= value
+func Lte(column string, value interface{}) Condition {
+	return &compareCond{column: column, op: "<=", value: value}
+}
+
+// Like column LIKE pattern
+func Like(column string, pattern string) Condition {
+	return &compareCond{column: column, op: "LIKE", value: pattern}
+}
+
+// NotLike column NOT LIKE pattern
+func NotLike(column string, pattern string) Condition {
+	return &compareCond{column: column, op: "NOT LIKE", value: pattern}
+}
+
+type inCond struct {
+	column string
+	not    bool
+	values []interface{}
+}
+
+func (c *inCond) Build(d Dialect) (string, []interface{}) {
+	// an empty IN list matches nothing and an empty NOT IN list matches everything
+	if len(c.values) == 0 {
+		if c.not {
+			return "1=1", nil
+		}
+		return "1=0", nil
+	}
+
+	op := "IN"
+	if c.not {
+		op = "NOT IN"
+	}
+	return fmt.Sprintf("%s %s (%s)", quoteColumn(d, c.column), op, bindVars(len(c.values))), c.values
+}
+
+// flattenValues expands a single slice argument, for example In("id", []int{1, 2})
+func flattenValues(values []interface{}) []interface{} {
+	if len(values) != 1 {
+		return values
+	}
+
+	v := reflect.ValueOf(values[0])
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() == reflect.Uint8 {
+		return values
+	}
+
+	flat := make([]interface{}, v.Len())
+	for i := range flat {
+		flat[i] = v.Index(i).Interface()
+	}
+	return flat
+}
+
+// In column IN (values), values can be a slice, for example In("id", []int{1, 2}) or In("id", 1, 2)
+func In(column string, values ...interface{}) Condition {
+	return &inCond{column: column, values: flattenValues(values)}
+}
+
+// NotIn column NOT IN (values)
+func NotIn(column string, values ...interface{}) Condition {
+	return &inCond{column: column, not: true, values: flattenValues(values)}
+}
+
+type betweenCond struct {
+	column string
+	from   interface{}
+	to     interface{}
+}
+
+func (c *betweenCond) Build(d Dialect) (string, []interface{}) {
+	return fmt.Sprintf("%s BETWEEN ? AND ?", quoteColumn(d, c.column)), []interface{}{c.from, c.to}
+}
+
+// Between column BETWEEN from AND to
+func Between(column string, from, to interface{}) Condition {
+	return &betweenCond{column: column, from: from, to: to}
+}
+
+type nullCond struct {
+	column string
+	not    bool
+}
+
+func (c *nullCond) Build(d Dialect) (string, []interface{}) {
+	if c.not {
+		return quoteColumn(d, c.column) + " IS NOT NULL", nil
+	}
+	return quoteColumn(d, c.column) + " IS NULL", nil
+}
+
+// IsNull column IS NULL
+func IsNull(column string) Condition {
+	return &nullCond{column: column}
+}
+
+// IsNotNull column IS NOT NULL
+func IsNotNull(column string) Condition {
+	return &nullCond{column: column, not: true}
+}
+
+type groupCond struct {
+	op    string
+	conds []Condition
+}
+
+func (c *groupCond) Build(d Dialect) (string, []interface{}) {
+	var parts []string
+	var args []interface{}
+	for _, cond := range c.conds {
+		if cond == nil {
+			continue
+		}
+
+		str, condArgs := cond.Build(d)
+		if str == "" {
+			continue
+		}
+		parts = append(parts, str)
+		args = append(args, condArgs...)
+	}
+
+	switch len(parts) {
+	case 0:
+		return "", nil
+	case 1:
+		return parts[0], args
+	}
+	return "(" + strings.Join(parts, ") "+c.op+" (") + ")", args
+}
+
+// And joins the conditions with AND, nil and empty conditions are skipped,
+// so optional filters can be built as nil
+func And(conds ...Condition) Condition {
+	return &groupCond{op: "AND", conds: conds}
+}
+
+// Or joins the conditions with OR, nil and empty conditions are skipped
+func Or(conds ...Condition) Condition {
+	return &groupCond{op: "OR", conds: conds}
+}
+
+type notCond struct {
+	cond Condition
+}
+
+func (c *notCond) Build(d Dialect) (string, []interface{}) {
+	str, args := c.cond.Build(d)
+	if str == "" {
+		return "", nil
+	}
+	return "NOT (" + str + ")", args
+}
+
+// Not negates the condition
+func Not(cond Condition) Condition {
+	return &notCond{cond: cond}
+}
@@ -0,0 +1,71 @@
+package gosql
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCondition_Build(t *testing.T) {
+	tests := []struct {
+		cond  Condition
+		query string
+		args  string
+	}{
+		{Eq("id", 1), "`id` = ?", "[1]"},
+		{Eq("u.id", 1), "`u`.`id` = ?", "[1]"},
+		{Eq("deleted_at", nil), "`deleted_at` IS NULL", "[]"},
+		{Neq("status", 2), "`status` <> ?", "[2]"},
+		{Gt("id", 1), "`id` > ?", "[1]"},
+		{Gte("id", 1), "`id` >= ?", "[1]"},
+		{Lt("id", 1), "`id` < ?", "[1]"},
+		{Lte("id", 1), "`id` <= ?", "[1]"},
+		{Like("name", "test%"), "`name` LIKE ?", "[test%]"},
+		{NotLike("name", "test%"), "`name` NOT LIKE ?", "[test%]"},
+		{In("id", []int{1, 2, 3}), "`id` IN (?,?,?)", "[1 2 3]"},
+		{In("id", 1, 2), "`id` IN (?,?)", "[1 2]"},
+		{In("id", []int{}), "1=0", "[]"},
+		{NotIn("id", []string{"a"}), "`id` NOT IN (?)", "[a]"},
+		{NotIn("id"), "1=1", "[]"},
+		{Between("id", 1, 10), "`id` BETWEEN ? AND ?", "[1 10]"},
+		{IsNull("success_time"), "`success_time` IS NULL", "[]"},
+		{IsNotNull("success_time"), "`success_time` IS NOT NULL", "[]"},
+		{Eq("like_total", Expr("comment_total + ?", 1)), "`like_total` = comment_total + ?", "[1]"},
+		{And(Eq("status", 1), nil, And()), "`status` = ?", "[1]"},
+		{And(), "", "[]"},
+		{Or(Eq("status", 1), In("id", []int{1, 2})), "(`status` = ?) OR (`id` IN (?,?))", "[1 1 2]"},
+		{And(Eq("status", 1), Or(Eq("id", 1), Expr("name = ?", "test"))), "(`status` = ?) AND ((`id` = ?) OR (name = ?))", "[1 1 test]"},
+		{Not(In("id", 1, 2)), "NOT (`id` IN (?,?))", "[1 2]"},
+	}
+
+	d := mustGetDialect("mysql")
+	for _, tt := range tests {
+		query, args := tt.cond.Build(d)
+		if query != tt.query {
+			t.Errorf("Build() query = %s, want %s", query, tt.query)
+		}
+
+		if fmt.Sprint(args) != tt.args && !(len(args) == 0 && tt.args == "[]") {
+			t.Errorf("Build() args = %v, want %s", args, tt.args)
+		}
+	}
+}
+
+func TestSQLBuilder_WhereCondition(t *testing.T) {
+	b := &SQLBuilder{
+		dialect: mustGetDialect("sqlite3"),
+		table:   "users",
+	}
+
+	b.Where("status = ?", 1)
+	b.Where(And(Gte("id", 10), nil))
+	b.OrWhere(In("name", []string{"a", "b"}))
+	b.Where(Or())
+
+	if q := b.queryString(); q != `SELECT * FROM "users" WHERE (status = ?) AND ("id" >= ?) OR ("name" IN (?,?));` {
+		t.Error("sql builder condition query error", q)
+	}
+
+	if args := fmt.Sprint(b.queryArgs()); args != "[1 10 a b]" {
+		t.Error("sql builder condition args error", args)
+	}
+}
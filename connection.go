@@ -17,6 +17,7 @@ var defaultLink = "default"
 // If database fatal exit
 var FatalExit = true
 var dbService = make(map[string]*sqlx.DB, 0)
+var replicaService = make(map[string]*replicaSet, 0)
 
 // DB gets the specified database engine,
 // or the default DB if no name is specified.
@@ -39,9 +40,11 @@ func List() map[string]*sqlx.DB {
 }
 
 type Options struct {
-	maxOpenConns int
-	maxIdleConns int
-	maxLifetime  int
+	maxOpenConns  int
+	maxIdleConns  int
+	maxLifetime   int
+	replicas      []string
+	replicaPolicy ReplicaPolicy
 }
 
 type Option func(*Options)
@@ -64,14 +67,21 @@ func WithMaxLifetimes(i int) Option {
 	}
 }
 
-// Open gosql.DB with sqlx
-func Open(driver, dbSource string, opts ...Option) (*DB, error) {
+// WithReplicas set the read replicas dsn, read queries are sent to the replicas
+func WithReplicas(dsn ...string) Option {
+	return func(options *Options) {
+		options.replicas = append(options.replicas, dsn...)
+	}
+}
 
-	var options Options
-	for _, opt := range opts {
-		opt(&options)
+// WithReplicaPolicy set the policy for choosing a replica, default RoundRobin
+func WithReplicaPolicy(p ReplicaPolicy) Option {
+	return func(options *Options) {
+		options.replicaPolicy = p
 	}
+}
 
+func (options *Options) connect(driver, dbSource string) (*sqlx.DB, error) {
 	db, err := sqlx.Connect(driver, dbSource)
 	if err != nil {
 		return nil, err
@@ -89,7 +99,42 @@ func Open(driver, dbSource string, opts ...Option) (*DB, error) {
 		db.SetConnMaxLifetime(time.Duration(options.maxLifetime) * time.Second)
 	}
 
-	return &DB{database: db}, nil
+	return db, nil
+}
+
+// Open gosql.DB with sqlx
+func Open(driver, dbSource string, opts ...Option) (*DB, error) {
+
+	var options Options
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	db, err := options.connect(driver, dbSource)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(options.replicas) == 0 {
+		return &DB{database: db}, nil
+	}
+
+	replicas := &replicaSet{policy: options.replicaPolicy}
+	if replicas.policy == nil {
+		replicas.policy = RoundRobin()
+	}
+
+	for _, dsn := range options.replicas {
+		replica, err := options.connect(driver, dsn)
+		if err != nil {
+			_ = db.Close()
+			replicas.close()
+			return nil, err
+		}
+		replicas.dbs = append(replicas.dbs, replica)
+	}
+
+	return &DB{database: db, replicas: replicas}, nil
 }
 
 // OpenWithDB open gosql.DB with sql.DB
@@ -127,17 +172,53 @@ func Connect(configs map[string]*Config) (err error) {
 			logger.SetLogging(true)
 		}
 
-		sess.SetMaxOpenConns(conf.MaxOpenConns)
-		sess.SetMaxIdleConns(conf.MaxIdleConns)
-		if conf.MaxLifetime > 0 {
-			sess.SetConnMaxLifetime(time.Duration(conf.MaxLifetime) * time.Second)
-		}
+		setPool(sess, conf)
 
 		if db, ok := dbService[key]; ok {
 			_ = db.Close()
 		}
 
 		dbService[key] = sess
+
+		if replicas, ok := replicaService[key]; ok {
+			replicas.close()
+			delete(replicaService, key)
+		}
+
+		if len(conf.Replicas) == 0 {
+			continue
+		}
+
+		policy, err := newReplicaPolicy(conf.ReplicaPolicy)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		replicas := &replicaSet{policy: policy}
+		for i, dsn := range conf.Replicas {
+			replica, err := sqlx.Connect(conf.Driver, dsn)
+			if err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			log.Printf("[db] connect:%s replica %d", key, i)
+
+			setPool(replica, conf)
+			replicas.dbs = append(replicas.dbs, replica)
+		}
+
+		if len(replicas.dbs) > 0 {
+			replicaService[key] = replicas
+		}
 	}
 	return
 }
+
+func setPool(db *sqlx.DB, conf *Config) {
+	db.SetMaxOpenConns(conf.MaxOpenConns)
+	db.SetMaxIdleConns(conf.MaxIdleConns)
+	if conf.MaxLifetime > 0 {
+		db.SetConnMaxLifetime(time.Duration(conf.MaxLifetime) * time.Second)
+	}
+}
@@ -0,0 +1,244 @@
+package gosql
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned by All when the cursor token can not be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// cursorColumn the order column of the keyset pagination
+type cursorColumn struct {
+	name string
+	desc bool
+}
+
+// Cursor keyset pagination, the rows after the cursor returned by NextCursor are selected
+// in the order of the columns, a column can be followed by desc, the last column must be unique, for example:
+// b := gosql.Model(&moments).Cursor(token, "created_at desc", "id desc").Limit(20)
+// b.All()
+// next, err := b.NextCursor()
+func (b *Builder) Cursor(after string, columns ...string) *Builder {
+	b.cursorColumns = make([]cursorColumn, 0, len(columns))
+	orders := make([]string, 0, len(columns))
+	for _, col := range columns {
+		parts := strings.Fields(col)
+		if len(parts) == 0 {
+			continue
+		}
+		c := cursorColumn{name: parts[0], desc: len(parts) > 1 && strings.EqualFold(parts[1], "desc")}
+		b.cursorColumns = append(b.cursorColumns, c)
+		if c.desc {
+			orders = append(orders, b.quoteColumn(c.name)+" DESC")
+		} else {
+			orders = append(orders, b.quoteColumn(c.name))
+		}
+	}
+	b.OrderBy(strings.Join(orders, ","))
+
+	b.cursorValues = nil
+	if after != "" {
+		b.cursorValues, b.cursorErr = decodeCursor(after)
+		if b.cursorErr == nil && len(b.cursorValues) != len(b.cursorColumns) {
+			b.cursorErr = fmt.Errorf("%w: %d values for %d columns", ErrInvalidCursor, len(b.cursorValues), len(b.cursorColumns))
+		}
+	}
+	return b
+}
+
+// NextCursor returns the cursor of the last row selected by All, it is empty if there are no more rows
+func (b *Builder) NextCursor() (string, error) {
+	if len(b.cursorColumns) == 0 {
+		return "", nil
+	}
+
+	rows := reflect.Indirect(reflect.ValueOf(b.model))
+	if rows.Kind() == reflect.Interface {
+		rows = reflect.Indirect(rows.Elem())
+	}
+	if rows.Kind() != reflect.Slice || rows.Len() == 0 {
+		return "", nil
+	}
+	if limit, err := strconv.Atoi(b.limit); err == nil && rows.Len() < limit {
+		return "", nil
+	}
+
+	fields := mapper.FieldMap(rows.Index(rows.Len() - 1))
+	values := make([]interface{}, len(b.cursorColumns))
+	for i, c := range b.cursorColumns {
+		name := c.name
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			name = name[i+1:]
+		}
+
+		field, ok := fields[name]
+		if !ok {
+			return "", fmt.Errorf("cursor column %s is not a field of the model", c.name)
+		}
+
+		value := reflect.Indirect(field).Interface()
+		if v, ok := value.(driver.Valuer); ok {
+			var err error
+			if value, err = v.Value(); err != nil {
+				return "", err
+			}
+		}
+		values[i] = value
+	}
+	return encodeCursor(values)
+}
+
+// cursorScope adds the keyset condition of the cursor to the where clause
+func (b *Builder) cursorScope() error {
+	if b.cursorErr != nil {
+		return b.cursorErr
+	}
+	if len(b.cursorValues) == 0 {
+		return nil
+	}
+
+	query, args := cursorCondition(b.dialect, b.cursorColumns, b.cursorValues)
+	b.scope(query, args...)
+	return nil
+}
+
+// cursorCondition (a, b) > (?, ?) if the columns are in the same direction and the database supports row values,
+// otherwise a > ? OR (a = ? AND b > ?)
+func cursorCondition(d sqlDialect, columns []cursorColumn, values []interface{}) (string, []interface{}) {
+	op := func(c cursorColumn) string {
+		if c.desc {
+			return "<"
+		}
+		return ">"
+	}
+
+	sameDirection := true
+	for _, c := range columns {
+		sameDirection = sameDirection && c.desc == columns[0].desc
+	}
+
+	if sameDirection && d.Supports(FeatureRowValues) {
+		cols := make([]string, len(columns))
+		for i, c := range columns {
+			cols[i] = quoteColumn(d, c.name)
+		}
+		return fmt.Sprintf("(%s) %s (%s)", strings.Join(cols, ","), op(columns[0]), bindVars(len(columns))), values
+	}
+
+	var ors []string
+	var args []interface{}
+	for i, c := range columns {
+		var ands []string
+		for j := 0; j < i; j++ {
+			ands = append(ands, quoteColumn(d, columns[j].name)+" = ?")
+			args = append(args, values[j])
+		}
+		ands = append(ands, quoteColumn(d, c.name)+" "+op(c)+" ?")
+		args = append(args, values[i])
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+	return strings.Join(ors, " OR "), args
+}
+
+// cursorValue is a value of the cursor token, the types that JSON can not keep are tagged
+type cursorValue struct {
+	Type  string          `json:"t,omitempty"`
+	Value json.RawMessage `json:"v"`
+}
+
+const (
+	cursorTime  = "time"
+	cursorBytes = "bytes"
+)
+
+// encodeCursor the values are encoded by JSON and base64 so the token is opaque and URL safe
+func encodeCursor(values []interface{}) (string, error) {
+	items := make([]cursorValue, len(values))
+	for i, value := range values {
+		switch value.(type) {
+		case time.Time:
+			items[i].Type = cursorTime
+		case []byte:
+			items[i].Type = cursorBytes
+		case nil, bool, string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		default:
+			return "", fmt.Errorf("cursor value of type %T is not supported", value)
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		items[i].Value = data
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor the token is sent by the client, only the scalar values are accepted
+func decodeCursor(token string) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	var items []cursorValue
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	values := make([]interface{}, len(items))
+	for i, item := range items {
+		if values[i], err = item.decode(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+		}
+	}
+	return values, nil
+}
+
+func (c cursorValue) decode() (interface{}, error) {
+	switch c.Type {
+	case cursorTime:
+		var t time.Time
+		err := json.Unmarshal(c.Value, &t)
+		return t, err
+	case cursorBytes:
+		var b []byte
+		err := json.Unmarshal(c.Value, &b)
+		return b, err
+	case "":
+	default:
+		return nil, fmt.Errorf("unknown value type %s", c.Type)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(c.Value))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	switch v := value.(type) {
+	case nil, bool, string:
+		return v, nil
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, nil
+		}
+		return v.Float64()
+	}
+	return nil, fmt.Errorf("value %s is not a scalar", c.Value)
+}
@@ -0,0 +1,134 @@
+package gosql
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_cursorCondition(t *testing.T) {
+	asc := []cursorColumn{{name: "created_at"}, {name: "id"}}
+	mixed := []cursorColumn{{name: "created_at", desc: true}, {name: "m.id"}}
+	values := []interface{}{"2018-11-28 14:04:02", 1}
+
+	tests := []struct {
+		dialect string
+		columns []cursorColumn
+		values  []interface{}
+		query   string
+		args    []interface{}
+	}{
+		{"mysql", asc, values, "(`created_at`,`id`) > (?,?)", values},
+		{"mysql", []cursorColumn{{name: "id", desc: true}}, []interface{}{1}, "(`id`) < (?)", []interface{}{1}},
+		{"mssql", asc, values, "([created_at] > ?) OR ([created_at] = ? AND [id] > ?)", []interface{}{"2018-11-28 14:04:02", "2018-11-28 14:04:02", 1}},
+		{"mysql", mixed, values, "(`created_at` < ?) OR (`created_at` = ? AND `m`.`id` > ?)", []interface{}{"2018-11-28 14:04:02", "2018-11-28 14:04:02", 1}},
+	}
+
+	for _, tt := range tests {
+		query, args := cursorCondition(mustGetDialect(tt.dialect), tt.columns, tt.values)
+		if query != tt.query {
+			t.Errorf("%s cursor condition = %s, want %s", tt.dialect, query, tt.query)
+		}
+		if !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("%s cursor args = %v, want %v", tt.dialect, args, tt.args)
+		}
+	}
+}
+
+func Test_encodeCursor(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	token, err := encodeCursor([]interface{}{now, 10, "a", 1.5, []byte("b"), nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := decodeCursor(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(values) != 6 || !values[0].(time.Time).Equal(now) || !reflect.DeepEqual(values[1:], []interface{}{int64(10), "a", 1.5, []byte("b"), nil}) {
+		t.Error("decode cursor error", values)
+	}
+
+	if _, err := encodeCursor([]interface{}{struct{}{}}); err == nil {
+		t.Error("unsupported cursor value must return an error")
+	}
+
+	for _, token := range []string{
+		"!invalid",
+		base64.RawURLEncoding.EncodeToString([]byte(`[{"v":[1,2]}]`)),
+		base64.RawURLEncoding.EncodeToString([]byte(`[{"t":"unknown","v":1}]`)),
+	} {
+		if _, err := decodeCursor(token); !errors.Is(err, ErrInvalidCursor) {
+			t.Error("invalid cursor error", token, err)
+		}
+	}
+}
+
+func TestBuilder_Cursor(t *testing.T) {
+	token, _ := encodeCursor([]interface{}{10})
+	b := &Builder{SQLBuilder: SQLBuilder{dialect: mustGetDialect("mysql"), table: "moments"}}
+	b.Where("status = ?", 1).OrWhere("user_id = ?", 5).Cursor(token, "id desc").Limit(5)
+	if err := b.cursorScope(); err != nil {
+		t.Fatal(err)
+	}
+
+	if query := b.queryString(); query != "SELECT * FROM `moments` WHERE ((status = ?) OR (user_id = ?)) AND ((`id`) < (?)) ORDER BY `id` DESC LIMIT 5;" {
+		t.Error("cursor query error", query)
+	}
+
+	b = &Builder{SQLBuilder: SQLBuilder{dialect: mustGetDialect("mysql"), table: "moments"}}
+	if err := b.Cursor(token, "created_at", "id").cursorScope(); !errors.Is(err, ErrInvalidCursor) {
+		t.Error("cursor with wrong number of values error", err)
+	}
+}
+
+func TestBuilder_CursorAll(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		initDatas(t)
+
+		var ids []int
+		token := ""
+		for i := 0; i < 10; i++ {
+			moments := make([]*MomentList, 0)
+			b := Model(&moments).Relation("User", func(b *Builder) {
+				b.Where("status = 1")
+			}).Where("status = 1").Cursor(token, "created_at desc", "id desc").Limit(5)
+			if err := b.All(); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, m := range moments {
+				ids = append(ids, m.Id)
+			}
+
+			next, err := b.NextCursor()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next == "" {
+				break
+			}
+			token = next
+		}
+
+		num, err := Model(&MomentList{}).Where("status = 1").Count()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if int64(len(ids)) != num {
+			t.Error("cursor pagination must select every row once", len(ids), num)
+		}
+
+		for i := 1; i < len(ids); i++ {
+			if ids[i] >= ids[i-1] {
+				t.Error("cursor pagination order error", ids)
+				break
+			}
+		}
+	})
+}
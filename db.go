@@ -3,6 +3,7 @@ package gosql
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"log"
 	"reflect"
 	"time"
@@ -18,6 +19,13 @@ type ISqlx interface {
 	Exec(query string, args ...interface{}) (sql.Result, error)
 	NamedExec(query string, arg interface{}) (sql.Result, error)
 	Preparex(query string) (*sqlx.Stmt, error)
+	QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error)
+	QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+	PreparexContext(ctx context.Context, query string) (*sqlx.Stmt, error)
 	Rebind(query string) string
 	DriverName() string
 }
@@ -26,7 +34,13 @@ type BuilderChainFunc func(b *Builder)
 
 type DB struct {
 	database    *sqlx.DB
+	replicas    *replicaSet
+	primary     bool
 	tx          *sqlx.Tx
+	depth       int
+	parent      *DB
+	onCommit    []func()
+	onRollback  []func()
 	logging     bool
 	RelationMap map[string]BuilderChainFunc
 }
@@ -40,6 +54,16 @@ func (w *DB) db() ISqlx {
 	return w.database.Unsafe()
 }
 
+// return database instance for read queries, replicas are used unless
+// it is a transaction or UsePrimary is set
+func (w *DB) reader() ISqlx {
+	if w.tx != nil || w.primary || w.replicas == nil {
+		return w.db()
+	}
+
+	return w.replicas.pick().Unsafe()
+}
+
 // ShowSql single show sql log
 func ShowSql() *DB {
 	w := Use(defaultLink)
@@ -72,8 +96,31 @@ func (w *DB) ShowSql() *DB {
 	return w
 }
 
+// UsePrimary send read queries to the primary instead of the replicas,
+// for example reading data that has just been written, it returns a copy and the DB is not changed
+func (w *DB) UsePrimary() *DB {
+	return w.withPrimary()
+}
+
+// withPrimary returns a copy of the DB that reads from the primary, so the DB shared by other queries is not changed,
+// the transaction already reads from the primary and is not copied
+func (w *DB) withPrimary() *DB {
+	if w.tx != nil || w.primary {
+		return w
+	}
+
+	db := *w
+	db.primary = true
+	return &db
+}
+
 // Beginx begins a transaction and returns an *gosql.DB instead of an *sql.Tx.
+// If it is already a transaction, a savepoint is created as a nested transaction.
 func (w *DB) Begin() (*DB, error) {
+	if w.tx != nil {
+		return w.savepoint(context.Background())
+	}
+
 	tx, err := w.database.Beginx()
 	if err != nil {
 		return nil, err
@@ -81,14 +128,92 @@ func (w *DB) Begin() (*DB, error) {
 	return &DB{tx: tx}, nil
 }
 
-// Commit commits the transaction.
+// savepoint creates a savepoint in the transaction and returns it as a nested transaction
+func (w *DB) savepoint(ctx context.Context) (*DB, error) {
+	if d := w.dialect(); !d.Supports(FeatureSavepoint) {
+		return nil, unsupported(d, "savepoint")
+	}
+
+	sp := &DB{tx: w.tx, depth: w.depth + 1, parent: w, logging: w.logging}
+	_, err := sp.ExecContext(ctx, sp.dialect().Savepoint(sp.savepointName()))
+	if err != nil {
+		return nil, err
+	}
+	return sp, nil
+}
+
+func (w *DB) savepointName() string {
+	return fmt.Sprintf("gosql_sp_%d", w.depth)
+}
+
+func (w *DB) dialect() sqlDialect {
+	return newDialect(w.DriverName())
+}
+
+// Commit commits the transaction, a nested transaction releases its savepoint.
 func (w *DB) Commit() error {
-	return w.tx.Commit()
+	if w.depth > 0 {
+		var err error
+		// some databases release the savepoints with the transaction, such as SQL Server
+		if release := w.dialect().ReleaseSavepoint(w.savepointName()); release != "" {
+			_, err = w.Exec(release)
+		}
+		if err == nil {
+			// the callbacks run when the outermost transaction ends
+			w.parent.onCommit = append(w.parent.onCommit, w.onCommit...)
+			w.parent.onRollback = append(w.parent.onRollback, w.onRollback...)
+			w.onCommit, w.onRollback = nil, nil
+		}
+		return err
+	}
+
+	err := w.tx.Commit()
+	w.finish(err == nil)
+	return err
 }
 
-// Rollback aborts the transaction.
+// Rollback aborts the transaction, a nested transaction rolls back to its savepoint.
 func (w *DB) Rollback() error {
-	return w.tx.Rollback()
+	var err error
+	if w.depth > 0 {
+		_, err = w.Exec(w.dialect().RollbackToSavepoint(w.savepointName()))
+	} else {
+		err = w.tx.Rollback()
+	}
+	w.finish(false)
+	return err
+}
+
+// OnCommit registers fn to run after the transaction commits,
+// if it is not a transaction, fn runs immediately
+func (w *DB) OnCommit(fn func()) {
+	if w.tx == nil {
+		fn()
+		return
+	}
+	w.onCommit = append(w.onCommit, fn)
+}
+
+// OnRollback registers fn to run after the transaction rolls back,
+// if it is not a transaction, fn never runs
+func (w *DB) OnRollback(fn func()) {
+	if w.tx == nil {
+		return
+	}
+	w.onRollback = append(w.onRollback, fn)
+}
+
+// finish runs the commit or rollback callbacks in order, each callback runs only once
+func (w *DB) finish(committed bool) {
+	callbacks := w.onRollback
+	if committed {
+		callbacks = w.onCommit
+	}
+	w.onCommit, w.onRollback = nil, nil
+
+	for _, fn := range callbacks {
+		fn()
+	}
 }
 
 // Rebind wrapper sqlx.Rebind
@@ -101,8 +226,18 @@ func (w *DB) Preparex(query string) (*sqlx.Stmt, error) {
 	return w.db().Preparex(query)
 }
 
+// PreparexContext wrapper sqlx.PreparexContext
+func (w *DB) PreparexContext(ctx context.Context, query string) (*sqlx.Stmt, error) {
+	return w.db().PreparexContext(ctx, query)
+}
+
 // Exec wrapper sqlx.Exec
 func (w *DB) Exec(query string, args ...interface{}) (result sql.Result, err error) {
+	return w.ExecContext(context.Background(), query, args...)
+}
+
+// ExecContext wrapper sqlx.ExecContext
+func (w *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (result sql.Result, err error) {
 	defer func(start time.Time) {
 		logger.Log(&QueryStatus{
 			Query: query,
@@ -114,11 +249,16 @@ func (w *DB) Exec(query string, args ...interface{}) (result sql.Result, err err
 
 	}(time.Now())
 
-	return w.db().Exec(query, args...)
+	return w.db().ExecContext(ctx, query, args...)
 }
 
 // NamedExec wrapper sqlx.Exec
 func (w *DB) NamedExec(query string, args interface{}) (result sql.Result, err error) {
+	return w.NamedExecContext(context.Background(), query, args)
+}
+
+// NamedExecContext wrapper sqlx.NamedExecContext
+func (w *DB) NamedExecContext(ctx context.Context, query string, args interface{}) (result sql.Result, err error) {
 	defer func(start time.Time) {
 		logger.Log(&QueryStatus{
 			Query: query,
@@ -130,11 +270,16 @@ func (w *DB) NamedExec(query string, args interface{}) (result sql.Result, err e
 
 	}(time.Now())
 
-	return w.db().NamedExec(query, args)
+	return w.db().NamedExecContext(ctx, query, args)
 }
 
 // Queryx wrapper sqlx.Queryx
 func (w *DB) Queryx(query string, args ...interface{}) (rows *sqlx.Rows, err error) {
+	return w.QueryxContext(context.Background(), query, args...)
+}
+
+// QueryxContext wrapper sqlx.QueryxContext
+func (w *DB) QueryxContext(ctx context.Context, query string, args ...interface{}) (rows *sqlx.Rows, err error) {
 	defer func(start time.Time) {
 		logger.Log(&QueryStatus{
 			Query: query,
@@ -150,11 +295,16 @@ func (w *DB) Queryx(query string, args ...interface{}) (rows *sqlx.Rows, err err
 		return nil, err
 	}
 
-	return w.db().Queryx(query, newArgs...)
+	return w.reader().QueryxContext(ctx, query, newArgs...)
 }
 
 // QueryRowx wrapper sqlx.QueryRowx
 func (w *DB) QueryRowx(query string, args ...interface{}) (rows *sqlx.Row) {
+	return w.QueryRowxContext(context.Background(), query, args...)
+}
+
+// QueryRowxContext wrapper sqlx.QueryRowxContext
+func (w *DB) QueryRowxContext(ctx context.Context, query string, args ...interface{}) (rows *sqlx.Row) {
 	defer func(start time.Time) {
 		logger.Log(&QueryStatus{
 			Query: query,
@@ -167,11 +317,47 @@ func (w *DB) QueryRowx(query string, args ...interface{}) (rows *sqlx.Row) {
 
 	query, newArgs, _ := w.argsIn(query, args)
 
-	return w.db().QueryRowx(query, newArgs...)
+	return w.reader().QueryRowxContext(ctx, query, newArgs...)
+}
+
+// queryRowPrimary scans the row returned by a write statement, such as INSERT ... RETURNING,
+// or a read that must see the write, it is always executed on the primary
+func (w *DB) queryRowPrimary(ctx context.Context, dest []interface{}, query string, args ...interface{}) (err error) {
+	defer func(start time.Time) {
+		logger.Log(&QueryStatus{
+			Query: query,
+			Args:  args,
+			Err:   err,
+			Start: start,
+			End:   time.Now(),
+		}, w.logging)
+	}(time.Now())
+
+	return w.db().QueryRowxContext(ctx, query, args...).Scan(dest...)
+}
+
+// queryPrimary runs the query that writes rows on the primary, such as INSERT ... RETURNING of multiple rows
+func (w *DB) queryPrimary(ctx context.Context, query string, args ...interface{}) (rows *sqlx.Rows, err error) {
+	defer func(start time.Time) {
+		logger.Log(&QueryStatus{
+			Query: query,
+			Args:  args,
+			Err:   err,
+			Start: start,
+			End:   time.Now(),
+		}, w.logging)
+	}(time.Now())
+
+	return w.db().QueryxContext(ctx, query, args...)
 }
 
 // Get wrapper sqlx.Get
 func (w *DB) Get(dest interface{}, query string, args ...interface{}) (err error) {
+	return w.GetContext(context.Background(), dest, query, args...)
+}
+
+// GetContext wrapper sqlx.GetContext
+func (w *DB) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) (err error) {
 	defer func(start time.Time) {
 		logger.Log(&QueryStatus{
 			Query: query,
@@ -187,7 +373,7 @@ func (w *DB) Get(dest interface{}, query string, args ...interface{}) (err error
 		dest = wrapper.model
 	}
 
-	hook := NewHook(nil, w)
+	hook := NewHook(ctx, w)
 	refVal := reflect.ValueOf(dest)
 	hook.callMethod("BeforeFind", refVal)
 
@@ -196,14 +382,14 @@ func (w *DB) Get(dest interface{}, query string, args ...interface{}) (err error
 		return err
 	}
 
-	err = w.db().Get(dest, query, newArgs...)
+	err = w.reader().GetContext(ctx, dest, query, newArgs...)
 	if err != nil {
 		return err
 	}
 
 	if reflect.Indirect(refVal).Kind() == reflect.Struct {
 		// relation data fill
-		err = RelationOne(wrapper, w, dest)
+		err = RelationOneContext(ctx, wrapper, w, dest)
 	}
 
 	if err != nil {
@@ -227,6 +413,11 @@ func indirectType(v reflect.Type) reflect.Type {
 
 // Select wrapper sqlx.Select
 func (w *DB) Select(dest interface{}, query string, args ...interface{}) (err error) {
+	return w.SelectContext(context.Background(), dest, query, args...)
+}
+
+// SelectContext wrapper sqlx.SelectContext
+func (w *DB) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) (err error) {
 	defer func(start time.Time) {
 		logger.Log(&QueryStatus{
 			Query: query,
@@ -247,7 +438,7 @@ func (w *DB) Select(dest interface{}, query string, args ...interface{}) (err er
 		dest = wrapper.model
 	}
 
-	err = w.db().Select(dest, query, newArgs...)
+	err = w.reader().SelectContext(ctx, dest, query, newArgs...)
 	if err != nil {
 		return err
 	}
@@ -256,7 +447,7 @@ func (w *DB) Select(dest interface{}, query string, args ...interface{}) (err er
 	if t.Kind() == reflect.Slice {
 		if indirectType(t.Elem()).Kind() == reflect.Struct {
 			// relation data fill
-			err = RelationAll(wrapper, w, dest)
+			err = RelationAllContext(ctx, wrapper, w, dest)
 		}
 	}
 
@@ -268,22 +459,34 @@ func (w *DB) Select(dest interface{}, query string, args ...interface{}) (err er
 }
 
 // Txx the transaction with context
+// If it is already a transaction, fn runs in a savepoint and an error only rolls back to the savepoint
 func (w *DB) Txx(ctx context.Context, fn func(ctx context.Context, tx *DB) error) (err error) {
-	tx, err := w.database.BeginTxx(ctx, nil)
+	if w.tx != nil {
+		return w.nested(ctx, fn)
+	}
+
+	return w.txx(ctx, nil, fn)
+}
+
+// txx begins a transaction with options and commits it if fn returns nil
+func (w *DB) txx(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context, tx *DB) error) (err error) {
+	sqlxTx, err := w.database.BeginTxx(ctx, opts)
 
 	if err != nil {
 		return err
 	}
+
+	tx := &DB{tx: sqlxTx}
 	defer func() {
 		if err != nil {
 			err := tx.Rollback()
-			if err != nil {
+			if err != nil && err != sql.ErrTxDone {
 				log.Printf("gosql rollback error:%s", err)
 			}
 		}
 	}()
 
-	err = fn(ctx, &DB{tx: tx})
+	err = fn(ctx, tx)
 	if err == nil {
 		err = tx.Commit()
 	}
@@ -291,8 +494,22 @@ func (w *DB) Txx(ctx context.Context, fn func(ctx context.Context, tx *DB) error
 }
 
 // Tx the transaction
+// If it is already a transaction, fn runs in a savepoint and an error only rolls back to the savepoint
 func (w *DB) Tx(fn func(w *DB) error) (err error) {
-	tx, err := w.database.Beginx()
+	if w.tx != nil {
+		return w.nested(context.Background(), func(ctx context.Context, tx *DB) error {
+			return fn(tx)
+		})
+	}
+
+	return w.txx(context.Background(), nil, func(ctx context.Context, tx *DB) error {
+		return fn(tx)
+	})
+}
+
+// nested runs fn in a savepoint of the transaction
+func (w *DB) nested(ctx context.Context, fn func(ctx context.Context, tx *DB) error) (err error) {
+	tx, err := w.savepoint(ctx)
 	if err != nil {
 		return err
 	}
@@ -304,7 +521,8 @@ func (w *DB) Tx(fn func(w *DB) error) (err error) {
 			}
 		}
 	}()
-	err = fn(&DB{tx: tx})
+
+	err = fn(ctx, tx)
 	if err == nil {
 		err = tx.Commit()
 	}
@@ -352,7 +570,12 @@ func Begin() (*DB, error) {
 
 // Use is change database
 func Use(db string) *DB {
-	return &DB{database: Sqlx(db)}
+	return &DB{database: Sqlx(db), replicas: replicaService[db]}
+}
+
+// UsePrimary default database read queries from the primary
+func UsePrimary() *DB {
+	return Use(defaultLink).UsePrimary()
 }
 
 // Exec default database
@@ -360,21 +583,41 @@ func Exec(query string, args ...interface{}) (sql.Result, error) {
 	return Use(defaultLink).Exec(query, args...)
 }
 
+// ExecContext default database
+func ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return Use(defaultLink).ExecContext(ctx, query, args...)
+}
+
 // Exec default database
 func NamedExec(query string, args interface{}) (sql.Result, error) {
 	return Use(defaultLink).NamedExec(query, args)
 }
 
+// NamedExecContext default database
+func NamedExecContext(ctx context.Context, query string, args interface{}) (sql.Result, error) {
+	return Use(defaultLink).NamedExecContext(ctx, query, args)
+}
+
 // Queryx default database
 func Queryx(query string, args ...interface{}) (*sqlx.Rows, error) {
 	return Use(defaultLink).Queryx(query, args...)
 }
 
+// QueryxContext default database
+func QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	return Use(defaultLink).QueryxContext(ctx, query, args...)
+}
+
 // QueryRowx default database
 func QueryRowx(query string, args ...interface{}) *sqlx.Row {
 	return Use(defaultLink).QueryRowx(query, args...)
 }
 
+// QueryRowxContext default database
+func QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
+	return Use(defaultLink).QueryRowxContext(ctx, query, args...)
+}
+
 // Txx default database the transaction with context
 func Txx(ctx context.Context, fn func(ctx context.Context, tx *DB) error) error {
 	return Use(defaultLink).Txx(ctx, fn)
@@ -390,11 +633,21 @@ func Get(dest interface{}, query string, args ...interface{}) error {
 	return Use(defaultLink).Get(dest, query, args...)
 }
 
+// GetContext default database
+func GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return Use(defaultLink).GetContext(ctx, dest, query, args...)
+}
+
 // Select default database
 func Select(dest interface{}, query string, args ...interface{}) error {
 	return Use(defaultLink).Select(dest, query, args...)
 }
 
+// SelectContext default database
+func SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return Use(defaultLink).SelectContext(ctx, dest, query, args...)
+}
+
 // Relation association table builder handle
 func Relation(name string, fn BuilderChainFunc) *DB {
 	w := Use(defaultLink)
@@ -526,3 +526,98 @@ func TestRelation(t *testing.T) {
 		}
 	})
 }
+
+func TestDB_Context(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		insert(1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		user := &models.Users{}
+		if err := GetContext(ctx, user, "select * from users where id = ?", 1); err != context.Canceled {
+			t.Error("GetContext must be canceled", err)
+		}
+
+		if _, err := ExecContext(ctx, "update users set status = 2 where id = ?", 1); err != context.Canceled {
+			t.Error("ExecContext must be canceled", err)
+		}
+
+		if err := WithContext(ctx).Model(user).Where("id = ?", 1).Get(); err != context.Canceled {
+			t.Error("builder Get must be canceled", err)
+		}
+
+		if _, err := WithContext(ctx).Table("users").Count(); err != context.Canceled {
+			t.Error("mapper Count must be canceled", err)
+		}
+	})
+}
+
+func TestTx_Nested(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		err := Tx(func(tx *DB) error {
+			if _, err := tx.Model(&models.Users{Id: 1, Name: "test1"}).Create(); err != nil {
+				return err
+			}
+
+			err := tx.Tx(func(tx *DB) error {
+				if _, err := tx.Model(&models.Users{Id: 2, Name: "test2"}).Create(); err != nil {
+					return err
+				}
+				return errors.New("rollback to savepoint")
+			})
+			if err == nil {
+				t.Error("nested transaction must return the error")
+			}
+
+			return tx.Txx(context.Background(), func(ctx context.Context, tx *DB) error {
+				_, err := tx.Model(&models.Users{Id: 3, Name: "test3"}).Create()
+				return err
+			})
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		num, err := Model(&models.Users{}).Count()
+		if err != nil {
+			t.Error(err)
+		}
+
+		if num != 2 {
+			t.Error("nested transaction rollback failed", num)
+		}
+	})
+}
+
+func TestDB_BeginNested(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		tx, err := Begin()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sp, err := tx.Begin()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := sp.Model(&models.Users{Id: 1, Name: "test1"}).Create(); err != nil {
+			t.Error(err)
+		}
+
+		if err := sp.Rollback(); err != nil {
+			t.Error(err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+
+		num, _ := Model(&models.Users{}).Count()
+		if num != 0 {
+			t.Error("savepoint rollback failed")
+		}
+	})
+}
@@ -1,10 +1,52 @@
 package gosql
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 )
 
-// Dialect interface contains behaviors that differ across SQL database
+// Feature is an optional capability of the database
+type Feature int
+
+const (
+	// FeatureReturning the insert statement can return columns by RETURNING
+	FeatureReturning Feature = iota
+	// FeatureLastInsertId the driver reports the auto increment id by sql.Result.LastInsertId
+	FeatureLastInsertId
+	// FeatureSavepoint nested transactions by savepoints
+	FeatureSavepoint
+	// FeatureUpsert the insert statement can update the conflicting row
+	FeatureUpsert
+	// FeatureRowValues row value comparison, such as (a, b) > (?, ?)
+	FeatureRowValues
+	// FeatureRowLock SELECT ... FOR UPDATE and FOR SHARE
+	FeatureRowLock
+)
+
+// ErrUnsupported is returned when the database does not support the operation
+var ErrUnsupported = errors.New("operation is not supported by the database")
+
+// unsupported returns ErrUnsupported with the operation and dialect name
+func unsupported(d Dialect, operation string) error {
+	return fmt.Errorf("%w: %s on %s", ErrUnsupported, operation, d.GetName())
+}
+
+// Dialect interface contains behaviors that differ across SQL database,
+// the other behaviors are optional, a dialect implements the methods of the database that differ from the defaults:
+//
+// BindVar(i int) string                                  the i-th (1-based) bind var, default Placeholder()
+// Savepoint, RollbackToSavepoint, ReleaseSavepoint(name) the savepoint statements, default SAVEPOINT name
+// IsRetryable(err error) bool                            whether the transaction can be retried, default false
+// OnConflict(columns, set), Excluded(column)             the upsert clause, default ON CONFLICT ... DO UPDATE SET
+// Supports(f Feature) bool                               the optional features, default all except RETURNING
+// Returning(columns []string) string                     the RETURNING clause
+// LimitOffset(limit, offset string, ordered bool) string the pagination clause, default LIMIT x OFFSET y
+// IndexHint(index string) string                         the index hint after the table, ignored by default
+// OptimizerHint(hint string) (head, inline, tail string) the optimizer hint, default /*+ hint */ after SELECT
+// BoolLiteral(b bool) string                             the boolean literal, default TRUE and FALSE
+// Terminator() string                                    the statement terminator, default ";"
+// Lock(mode, option string) string                       the row lock clause, default FOR UPDATE or FOR SHARE
 type Dialect interface {
 	// GetName get dialect's name
 	GetName() string
@@ -12,10 +54,233 @@ type Dialect interface {
 	// Quote quotes field name to avoid SQL parsing exceptions by using a reserved word as a field name
 	Quote(key string) string
 
-	// Placeholder is where value holder default "?"
+	// Placeholder is where value holder default "?", it is used if the dialect does not implement BindVar
 	Placeholder() string
 }
 
+// bindVarDialect numbers the bind vars, such as $1 of postgres
+type bindVarDialect interface {
+	BindVar(i int) string
+}
+
+type savepointDialect interface {
+	Savepoint(name string) string
+	RollbackToSavepoint(name string) string
+	ReleaseSavepoint(name string) string
+}
+
+type retryableDialect interface {
+	IsRetryable(err error) bool
+}
+
+type onConflictDialect interface {
+	OnConflict(columns []string, set string) string
+}
+
+type excludedDialect interface {
+	Excluded(column string) string
+}
+
+type featureDialect interface {
+	Supports(f Feature) bool
+}
+
+type returningDialect interface {
+	Returning(columns []string) string
+}
+
+type paginationDialect interface {
+	LimitOffset(limit, offset string, ordered bool) string
+}
+
+type indexHintDialect interface {
+	IndexHint(index string) string
+}
+
+type optimizerHintDialect interface {
+	OptimizerHint(hint string) (head, inline, tail string)
+}
+
+type boolLiteralDialect interface {
+	BoolLiteral(b bool) string
+}
+
+type terminatorDialect interface {
+	Terminator() string
+}
+
+type lockDialect interface {
+	Lock(mode, option string) string
+}
+
+// sqlDialect is the dialect with all behaviors that the builders use, see fullDialect
+type sqlDialect interface {
+	Dialect
+	bindVarDialect
+	savepointDialect
+	retryableDialect
+	onConflictDialect
+	excludedDialect
+	featureDialect
+	returningDialect
+	paginationDialect
+	indexHintDialect
+	optimizerHintDialect
+	boolLiteralDialect
+	terminatorDialect
+	lockDialect
+}
+
+// mutationDialect is implemented by the dialect that updates and deletes by its own statements,
+// such as ALTER TABLE ... UPDATE of ClickHouse
+type mutationDialect interface {
+	UpdateStatement(table, set, where string) string
+	DeleteStatement(table, where string) string
+}
+
+// outputDialect is implemented by the dialect that returns the inserted columns before VALUES,
+// such as OUTPUT INSERTED.id of SQL Server, instead of appending Returning
+type outputDialect interface {
+	Output(columns []string) string
+}
+
+// fullDialect returns the dialect with all behaviors, the behaviors that a registered dialect
+// does not implement fall back to the defaults of commonDialect
+func fullDialect(d Dialect) sqlDialect {
+	if full, ok := d.(sqlDialect); ok {
+		return full
+	}
+	return compatDialect{d}
+}
+
+// compatDialect adapts the dialect that only implements some behaviors, such as a dialect written for Dialect
+// with GetName, Quote and Placeholder
+type compatDialect struct {
+	Dialect
+}
+
+func (c compatDialect) BindVar(i int) string {
+	if d, ok := c.Dialect.(bindVarDialect); ok {
+		return d.BindVar(i)
+	}
+	return c.Placeholder()
+}
+
+func (c compatDialect) Savepoint(name string) string {
+	if d, ok := c.Dialect.(savepointDialect); ok {
+		return d.Savepoint(name)
+	}
+	return commonDialect{}.Savepoint(name)
+}
+
+func (c compatDialect) RollbackToSavepoint(name string) string {
+	if d, ok := c.Dialect.(savepointDialect); ok {
+		return d.RollbackToSavepoint(name)
+	}
+	return commonDialect{}.RollbackToSavepoint(name)
+}
+
+func (c compatDialect) ReleaseSavepoint(name string) string {
+	if d, ok := c.Dialect.(savepointDialect); ok {
+		return d.ReleaseSavepoint(name)
+	}
+	return commonDialect{}.ReleaseSavepoint(name)
+}
+
+func (c compatDialect) IsRetryable(err error) bool {
+	if d, ok := c.Dialect.(retryableDialect); ok {
+		return d.IsRetryable(err)
+	}
+	return false
+}
+
+func (c compatDialect) OnConflict(columns []string, set string) string {
+	if d, ok := c.Dialect.(onConflictDialect); ok {
+		return d.OnConflict(columns, set)
+	}
+
+	cols := make([]string, len(columns))
+	for i, col := range columns {
+		cols[i] = c.Quote(col)
+	}
+	if set == "" {
+		return fmt.Sprintf("ON CONFLICT (%s) DO NOTHING", strings.Join(cols, ","))
+	}
+	return fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(cols, ","), set)
+}
+
+func (c compatDialect) Excluded(column string) string {
+	if d, ok := c.Dialect.(excludedDialect); ok {
+		return d.Excluded(column)
+	}
+	return "EXCLUDED." + c.Quote(column)
+}
+
+func (c compatDialect) Supports(f Feature) bool {
+	if d, ok := c.Dialect.(featureDialect); ok {
+		return d.Supports(f)
+	}
+	if f == FeatureReturning {
+		_, ok := c.Dialect.(returningDialect)
+		return ok
+	}
+	return true
+}
+
+func (c compatDialect) Returning(columns []string) string {
+	if d, ok := c.Dialect.(returningDialect); ok {
+		return d.Returning(columns)
+	}
+
+	cols := make([]string, len(columns))
+	for i, col := range columns {
+		cols[i] = c.Quote(col)
+	}
+	return "RETURNING " + strings.Join(cols, ",")
+}
+
+func (c compatDialect) LimitOffset(limit, offset string, ordered bool) string {
+	if d, ok := c.Dialect.(paginationDialect); ok {
+		return d.LimitOffset(limit, offset, ordered)
+	}
+	return commonDialect{}.LimitOffset(limit, offset, ordered)
+}
+
+func (c compatDialect) IndexHint(index string) string {
+	if d, ok := c.Dialect.(indexHintDialect); ok {
+		return d.IndexHint(index)
+	}
+	return ""
+}
+
+func (c compatDialect) OptimizerHint(hint string) (head, inline, tail string) {
+	if d, ok := c.Dialect.(optimizerHintDialect); ok {
+		return d.OptimizerHint(hint)
+	}
+	return commonDialect{}.OptimizerHint(hint)
+}
+
+func (c compatDialect) BoolLiteral(b bool) string {
+	if d, ok := c.Dialect.(boolLiteralDialect); ok {
+		return d.BoolLiteral(b)
+	}
+	return commonDialect{}.BoolLiteral(b)
+}
+
+func (c compatDialect) Terminator() string {
+	if d, ok := c.Dialect.(terminatorDialect); ok {
+		return d.Terminator()
+	}
+	return ";"
+}
+
+func (c compatDialect) Lock(mode, option string) string {
+	if d, ok := c.Dialect.(lockDialect); ok {
+		return d.Lock(mode, option)
+	}
+	return commonDialect{}.Lock(mode, option)
+}
+
 type commonDialect struct {
 }
 
@@ -27,10 +292,97 @@ func (commonDialect) Quote(key string) string {
 	return fmt.Sprintf(`"%s"`, key)
 }
 
-func (*commonDialect) Placeholder() string {
+func (commonDialect) Placeholder() string {
+	return "?"
+}
+
+func (commonDialect) BindVar(i int) string {
 	return "?"
 }
 
+func (commonDialect) Savepoint(name string) string {
+	return "SAVEPOINT " + name
+}
+
+func (commonDialect) RollbackToSavepoint(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+func (commonDialect) ReleaseSavepoint(name string) string {
+	return "RELEASE SAVEPOINT " + name
+}
+
+func (commonDialect) IsRetryable(err error) bool {
+	return false
+}
+
+// OnConflict ON CONFLICT (columns) DO UPDATE SET set, DO NOTHING if set is empty
+func (c commonDialect) OnConflict(columns []string, set string) string {
+	cols := make([]string, len(columns))
+	for i, col := range columns {
+		cols[i] = c.Quote(col)
+	}
+
+	if set == "" {
+		return fmt.Sprintf("ON CONFLICT (%s) DO NOTHING", strings.Join(cols, ","))
+	}
+	return fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(cols, ","), set)
+}
+
+func (c commonDialect) Excluded(column string) string {
+	return "EXCLUDED." + c.Quote(column)
+}
+
+func (commonDialect) Supports(f Feature) bool {
+	return f != FeatureReturning
+}
+
+func (c commonDialect) Returning(columns []string) string {
+	cols := make([]string, len(columns))
+	for i, col := range columns {
+		cols[i] = c.Quote(col)
+	}
+	return "RETURNING " + strings.Join(cols, ",")
+}
+
+// LimitOffset LIMIT limit OFFSET offset, the empty one is omitted
+func (commonDialect) LimitOffset(limit, offset string, ordered bool) string {
+	var parts []string
+	if limit != "" {
+		parts = append(parts, "LIMIT "+limit)
+	}
+	if offset != "" {
+		parts = append(parts, "OFFSET "+offset)
+	}
+	return strings.Join(parts, " ")
+}
+
+// IndexHint the index hint is ignored in compatibility mode
+func (commonDialect) IndexHint(index string) string {
+	return ""
+}
+
+// OptimizerHint /*+ hint */ after the SELECT keyword
+func (commonDialect) OptimizerHint(hint string) (head, inline, tail string) {
+	return "", "/*+ " + hint + " */", ""
+}
+
+func (commonDialect) BoolLiteral(b bool) string {
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+func (commonDialect) Terminator() string {
+	return ";"
+}
+
+// Lock FOR UPDATE and FOR SHARE with NOWAIT or SKIP LOCKED, such as MySQL 8 and Postgres
+func (commonDialect) Lock(mode, option string) string {
+	return joinClauses("FOR "+mode, option)
+}
+
 var dialectsMap = map[string]Dialect{}
 
 // RegisterDialect register new dialect
@@ -44,16 +396,16 @@ func GetDialect(name string) (dialect Dialect, ok bool) {
 	return
 }
 
-func mustGetDialect(name string) Dialect {
+func mustGetDialect(name string) sqlDialect {
 	if dialect, ok := dialectsMap[name]; ok {
-		return dialect
+		return fullDialect(dialect)
 	}
 	panic(fmt.Sprintf("`%v` is not officially supported", name))
 }
 
-func newDialect(name string) Dialect {
+func newDialect(name string) sqlDialect {
 	if value, ok := GetDialect(name); ok {
-		return value
+		return fullDialect(value)
 	}
 
 	fmt.Printf("`%v` is not officially supported, running under compatibility mode.\n", name)
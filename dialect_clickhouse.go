@@ -0,0 +1,63 @@
+package gosql
+
+import (
+	"fmt"
+)
+
+type clickhouseDialect struct {
+	commonDialect
+}
+
+func init() {
+	RegisterDialect("clickhouse", &clickhouseDialect{})
+}
+
+func (clickhouseDialect) GetName() string {
+	return "clickhouse"
+}
+
+func (clickhouseDialect) Quote(key string) string {
+	return fmt.Sprintf("`%s`", key)
+}
+
+// Supports ClickHouse has no auto increment id, transactions or upsert,
+// the rows should be inserted by large batches, such as Model(&rows).CreateInBatches(10000)
+func (clickhouseDialect) Supports(f Feature) bool {
+	return f == FeatureRowValues
+}
+
+// UpdateStatement ALTER TABLE ... UPDATE, the mutation is applied asynchronously
+func (clickhouseDialect) UpdateStatement(table, set, where string) string {
+	return fmt.Sprintf("ALTER TABLE %s UPDATE %s %s", table, set, mutationWhere(where))
+}
+
+// DeleteStatement ALTER TABLE ... DELETE, the mutation is applied asynchronously
+func (clickhouseDialect) DeleteStatement(table, where string) string {
+	return fmt.Sprintf("ALTER TABLE %s DELETE %s", table, mutationWhere(where))
+}
+
+// mutationWhere the WHERE clause is required by the mutations
+func mutationWhere(where string) string {
+	if where == "" {
+		return "WHERE 1"
+	}
+	return where
+}
+
+// OptimizerHint SETTINGS hint at the end of the statement, for example Hint("max_threads = 8")
+func (clickhouseDialect) OptimizerHint(hint string) (head, inline, tail string) {
+	return "", "", "SETTINGS " + hint
+}
+
+// BoolLiteral the booleans are stored as UInt8 before ClickHouse 21.12
+func (clickhouseDialect) BoolLiteral(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// Terminator the statements are sent without terminator, clickhouse-go parses the INSERT statements itself
+func (clickhouseDialect) Terminator() string {
+	return ""
+}
@@ -0,0 +1,56 @@
+package gosql
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_clickhouseDialect_Supports(t *testing.T) {
+	d := mustGetDialect("clickhouse")
+	for _, f := range []Feature{FeatureReturning, FeatureLastInsertId, FeatureSavepoint, FeatureUpsert} {
+		if d.Supports(f) {
+			t.Errorf("clickhouse must not support feature %d", f)
+		}
+	}
+
+	if !d.Supports(FeatureRowValues) {
+		t.Error("clickhouse must support row values")
+	}
+}
+
+func Test_clickhouseDialect_SQLBuilder(t *testing.T) {
+	b := &SQLBuilder{
+		dialect: mustGetDialect("clickhouse"),
+		table:   "events",
+	}
+	b.Where("user_id = ?", 1)
+
+	if query := b.updateString(map[string]interface{}{"status": 2}); query != "ALTER TABLE `events` UPDATE `status`=? WHERE (user_id = ?)" {
+		t.Error("clickhouse update error", query)
+	}
+
+	b = &SQLBuilder{
+		dialect: mustGetDialect("clickhouse"),
+		table:   "events",
+	}
+	if query := b.deleteString(); query != "ALTER TABLE `events` DELETE WHERE 1" {
+		t.Error("clickhouse delete error", query)
+	}
+
+	b.Where("created_at < ?", "2018-01-01")
+	if query := b.deleteString(); query != "ALTER TABLE `events` DELETE WHERE (created_at < ?)" {
+		t.Error("clickhouse delete where error", query)
+	}
+}
+
+func Test_clickhouseDialect_Unsupported(t *testing.T) {
+	m := &Mapper{SQLBuilder: SQLBuilder{dialect: mustGetDialect("clickhouse"), table: "events"}}
+	_, err := m.Upsert(map[string]interface{}{"id": 1}, []string{"id"}, nil)
+	if !errors.Is(err, ErrUnsupported) {
+		t.Error("clickhouse upsert must return ErrUnsupported", err)
+	}
+
+	if err.Error() != "operation is not supported by the database: upsert on clickhouse" {
+		t.Error("unsupported error message error", err)
+	}
+}
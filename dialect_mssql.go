@@ -0,0 +1,104 @@
+package gosql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type mssqlDialect struct {
+	commonDialect
+}
+
+func init() {
+	RegisterDialect("mssql", &mssqlDialect{})
+	RegisterDialect("sqlserver", &mssqlDialect{})
+}
+
+func (mssqlDialect) GetName() string {
+	return "mssql"
+}
+
+func (mssqlDialect) Quote(key string) string {
+	return fmt.Sprintf("[%s]", key)
+}
+
+func (mssqlDialect) BindVar(i int) string {
+	return fmt.Sprintf("@p%d", i)
+}
+
+func (mssqlDialect) Savepoint(name string) string {
+	return "SAVE TRANSACTION " + name
+}
+
+func (mssqlDialect) RollbackToSavepoint(name string) string {
+	return "ROLLBACK TRANSACTION " + name
+}
+
+// ReleaseSavepoint SQL Server releases the savepoints with the transaction
+func (mssqlDialect) ReleaseSavepoint(name string) string {
+	return ""
+}
+
+// IsRetryable deadlock victim (1205), the error of go-mssqldb implements SQLErrorNumber
+func (mssqlDialect) IsRetryable(err error) bool {
+	var e interface{ SQLErrorNumber() int32 }
+	if errors.As(err, &e) {
+		return e.SQLErrorNumber() == 1205
+	}
+	return false
+}
+
+// LimitOffset OFFSET ... ROWS FETCH NEXT ... ROWS ONLY, it requires ORDER BY
+func (mssqlDialect) LimitOffset(limit, offset string, ordered bool) string {
+	if limit == "" && offset == "" {
+		return ""
+	}
+
+	if offset == "" {
+		offset = "0"
+	}
+
+	clause := fmt.Sprintf("OFFSET %s ROWS", offset)
+	if limit != "" {
+		clause += fmt.Sprintf(" FETCH NEXT %s ROWS ONLY", limit)
+	}
+
+	if !ordered {
+		return "ORDER BY (SELECT NULL) " + clause
+	}
+	return clause
+}
+
+// Supports the inserted columns are returned by OUTPUT, go-mssqldb does not support LastInsertId,
+// upsert needs MERGE and row values are not supported
+func (mssqlDialect) Supports(f Feature) bool {
+	return f == FeatureReturning || f == FeatureSavepoint
+}
+
+// Output OUTPUT INSERTED.column, it is placed before VALUES of the insert statement
+func (m mssqlDialect) Output(columns []string) string {
+	cols := make([]string, len(columns))
+	for i, col := range columns {
+		cols[i] = "INSERTED." + m.Quote(col)
+	}
+	return "OUTPUT " + strings.Join(cols, ",")
+}
+
+// IndexHint WITH (INDEX(index))
+func (mssqlDialect) IndexHint(index string) string {
+	return fmt.Sprintf("WITH (INDEX(%s))", index)
+}
+
+// OptimizerHint OPTION (hint) at the end of the statement
+func (mssqlDialect) OptimizerHint(hint string) (head, inline, tail string) {
+	return "", "", "OPTION (" + hint + ")"
+}
+
+// BoolLiteral SQL Server has no boolean type, the bit values are used
+func (mssqlDialect) BoolLiteral(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
@@ -0,0 +1,99 @@
+package gosql
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_mssqlDialect_GetName(t *testing.T) {
+	for _, name := range []string{"mssql", "sqlserver"} {
+		if got := mustGetDialect(name).GetName(); got != "mssql" {
+			t.Errorf("%s GetName() = %v, want mssql", name, got)
+		}
+	}
+}
+
+func Test_mssqlDialect_LimitOffset(t *testing.T) {
+	tests := []struct {
+		limit   string
+		offset  string
+		ordered bool
+		want    string
+	}{
+		{"", "", false, ""},
+		{"10", "", true, "OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY"},
+		{"10", "20", true, "OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY"},
+		{"", "20", true, "OFFSET 20 ROWS"},
+		{"10", "", false, "ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY"},
+	}
+
+	d := mssqlDialect{}
+	for _, tt := range tests {
+		if got := d.LimitOffset(tt.limit, tt.offset, tt.ordered); got != tt.want {
+			t.Errorf("LimitOffset(%q, %q, %v) = %v, want %v", tt.limit, tt.offset, tt.ordered, got, tt.want)
+		}
+	}
+}
+
+func Test_mssqlDialect_SQLBuilder(t *testing.T) {
+	b := &SQLBuilder{
+		dialect: mustGetDialect("sqlserver"),
+		table:   "users",
+	}
+	b.Where("id > ? and status = ?", 1, 1)
+	b.order = "id desc"
+	b.limit = "10"
+	b.offset = "20"
+
+	if query := b.queryString(); query != "SELECT * FROM [users] WHERE (id > @p1 and status = @p2) ORDER BY id desc OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY;" {
+		t.Error("mssql query error", query)
+	}
+
+	b = &SQLBuilder{
+		dialect: mustGetDialect("mssql"),
+		table:   "users",
+	}
+	query := b.insertReturningString(map[string]interface{}{"name": "test", "status": 1}, []string{"id"})
+	if query != "INSERT INTO [users] ([name],[status]) OUTPUT INSERTED.[id] VALUES(@p1,@p2);" {
+		t.Error("mssql insert output error", query)
+	}
+
+	if args := fmt.Sprint(b.args); args != "[test 1]" {
+		t.Error("mssql insert output args error", args)
+	}
+}
+
+type mssqlError struct {
+	number int32
+}
+
+func (e mssqlError) Error() string {
+	return fmt.Sprintf("mssql: %d", e.number)
+}
+
+func (e mssqlError) SQLErrorNumber() int32 {
+	return e.number
+}
+
+func Test_mssqlDialect_Transaction(t *testing.T) {
+	d := mustGetDialect("mssql")
+	if got := d.Savepoint("sp_1"); got != "SAVE TRANSACTION sp_1" {
+		t.Error("mssql savepoint error", got)
+	}
+
+	if got := d.RollbackToSavepoint("sp_1"); got != "ROLLBACK TRANSACTION sp_1" {
+		t.Error("mssql rollback to savepoint error", got)
+	}
+
+	if got := d.ReleaseSavepoint("sp_1"); got != "" {
+		t.Error("mssql release savepoint must be empty", got)
+	}
+
+	if !d.IsRetryable(fmt.Errorf("exec: %w", mssqlError{1205})) {
+		t.Error("mssql deadlock must be retryable")
+	}
+
+	if d.IsRetryable(mssqlError{2627}) {
+		t.Error("mssql unique violation must not be retryable")
+	}
+}
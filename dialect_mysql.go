@@ -1,7 +1,10 @@
 package gosql
 
 import (
+	"errors"
 	"fmt"
+
+	"github.com/go-sql-driver/mysql"
 )
 
 type mysqlDialect struct {
@@ -19,3 +22,30 @@ func (mysqlDialect) GetName() string {
 func (mysqlDialect) Quote(key string) string {
 	return fmt.Sprintf("`%s`", key)
 }
+
+// IsRetryable deadlock found (1213) and lock wait timeout exceeded (1205)
+func (mysqlDialect) IsRetryable(err error) bool {
+	var e *mysql.MySQLError
+	if errors.As(err, &e) {
+		return e.Number == 1213 || e.Number == 1205
+	}
+	return false
+}
+
+// OnConflict ON DUPLICATE KEY UPDATE set, the conflict is detected by any unique key so columns is only
+// used to build a no-op assignment when set is empty
+func (m mysqlDialect) OnConflict(columns []string, set string) string {
+	if set == "" && len(columns) > 0 {
+		set = fmt.Sprintf("%s=%s", m.Quote(columns[0]), m.Quote(columns[0]))
+	}
+	return "ON DUPLICATE KEY UPDATE " + set
+}
+
+func (m mysqlDialect) Excluded(column string) string {
+	return fmt.Sprintf("VALUES(%s)", m.Quote(column))
+}
+
+// IndexHint force index(index)
+func (mysqlDialect) IndexHint(index string) string {
+	return fmt.Sprintf("force index(%s)", index)
+}
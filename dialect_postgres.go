@@ -1,10 +1,12 @@
 package gosql
 
-import "strconv"
+import (
+	"errors"
+	"strconv"
+)
 
 type postgresDialect struct {
 	commonDialect
-	count int
 }
 
 func init() {
@@ -15,7 +17,27 @@ func (postgresDialect) GetName() string {
 	return "postgres"
 }
 
-func (p *postgresDialect) Placeholder() string {
-	p.count++
-	return "$" + strconv.Itoa(p.count)
+func (postgresDialect) BindVar(i int) string {
+	return "$" + strconv.Itoa(i)
+}
+
+// IsRetryable serialization_failure (40001) and deadlock_detected (40P01),
+// the error of lib/pq and pgx both implement SQLState
+func (postgresDialect) IsRetryable(err error) bool {
+	var e interface{ SQLState() string }
+	if errors.As(err, &e) {
+		return e.SQLState() == "40001" || e.SQLState() == "40P01"
+	}
+	return false
+}
+
+// Supports lib/pq does not support LastInsertId, the primary key is returned by RETURNING
+func (postgresDialect) Supports(f Feature) bool {
+	return f != FeatureLastInsertId
+}
+
+// OptimizerHint the pg_hint_plan comment must be at the head of the statement,
+// Postgres has no index hints so IndexHint is ignored
+func (postgresDialect) OptimizerHint(hint string) (head, inline, tail string) {
+	return "/*+ " + hint + " */ ", "", ""
 }
@@ -1,5 +1,7 @@
 package gosql
 
+import "strings"
+
 type sqlite3Dialect struct {
 	commonDialect
 }
@@ -11,3 +13,40 @@ func init() {
 func (sqlite3Dialect) GetName() string {
 	return "sqlite3"
 }
+
+// IsRetryable SQLITE_BUSY and SQLITE_LOCKED
+func (sqlite3Dialect) IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "database is locked") || strings.Contains(msg, "database table is locked")
+}
+
+// Supports RETURNING requires SQLite 3.35+, it is only used if columns other than the primary key are returned
+func (sqlite3Dialect) Supports(f Feature) bool {
+	return true
+}
+
+// IndexHint INDEXED BY index
+func (sqlite3Dialect) IndexHint(index string) string {
+	return "INDEXED BY " + index
+}
+
+// OptimizerHint SQLite has no optimizer hints
+func (sqlite3Dialect) OptimizerHint(hint string) (head, inline, tail string) {
+	return "", "", ""
+}
+
+// BoolLiteral 1 and 0, TRUE and FALSE require SQLite 3.23+
+func (sqlite3Dialect) BoolLiteral(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// Lock SQLite has no row locks, the database is locked by the write transaction
+func (sqlite3Dialect) Lock(mode, option string) string {
+	return ""
+}
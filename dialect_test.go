@@ -1,6 +1,7 @@
 package gosql
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -11,3 +12,129 @@ func TestGetDialect(t *testing.T) {
 		t.Fatal("get dialect not mysql")
 	}
 }
+
+func TestDialect_Savepoint(t *testing.T) {
+	for _, name := range []string{"mysql", "postgres", "sqlite3"} {
+		d := mustGetDialect(name)
+		if got := d.Savepoint("sp_1"); got != "SAVEPOINT sp_1" {
+			t.Errorf("%s savepoint error: %s", name, got)
+		}
+		if got := d.RollbackToSavepoint("sp_1"); got != "ROLLBACK TO SAVEPOINT sp_1" {
+			t.Errorf("%s rollback to savepoint error: %s", name, got)
+		}
+		if got := d.ReleaseSavepoint("sp_1"); got != "RELEASE SAVEPOINT sp_1" {
+			t.Errorf("%s release savepoint error: %s", name, got)
+		}
+	}
+}
+
+func TestDialect_Returning(t *testing.T) {
+	tests := []struct {
+		name         string
+		returning    bool
+		lastInsertId bool
+	}{
+		{"mysql", false, true},
+		{"postgres", true, false},
+		{"sqlite3", true, true},
+	}
+
+	for _, tt := range tests {
+		d := mustGetDialect(tt.name)
+		if got := d.Supports(FeatureReturning); got != tt.returning {
+			t.Errorf("%s supports returning = %v, want %v", tt.name, got, tt.returning)
+		}
+		if got := d.Supports(FeatureLastInsertId); got != tt.lastInsertId {
+			t.Errorf("%s supports last insert id = %v, want %v", tt.name, got, tt.lastInsertId)
+		}
+	}
+
+	if got := mustGetDialect("postgres").Returning([]string{"id", "created_at"}); got != `RETURNING "id","created_at"` {
+		t.Error("postgres returning error", got)
+	}
+}
+
+func TestDialect_BoolLiteral(t *testing.T) {
+	testData := map[string]string{
+		"mysql":      "TRUE FALSE",
+		"postgres":   "TRUE FALSE",
+		"sqlite3":    "1 0",
+		"mssql":      "1 0",
+		"clickhouse": "1 0",
+	}
+
+	for k, v := range testData {
+		d := mustGetDialect(k)
+		if got := d.BoolLiteral(true) + " " + d.BoolLiteral(false); got != v {
+			t.Errorf("%s BoolLiteral = %s, want %s", k, got, v)
+		}
+	}
+}
+
+// minimalDialect is a dialect registered outside the package that only implements Dialect
+type minimalDialect struct{}
+
+func (minimalDialect) GetName() string {
+	return "minimal"
+}
+
+func (minimalDialect) Quote(key string) string {
+	return "`" + key + "`"
+}
+
+func (minimalDialect) Placeholder() string {
+	return "?"
+}
+
+// numberedDialect overrides some of the optional behaviors
+type numberedDialect struct {
+	minimalDialect
+}
+
+func (numberedDialect) BindVar(i int) string {
+	return fmt.Sprintf(":%d", i)
+}
+
+func (numberedDialect) BoolLiteral(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+func TestDialect_compat(t *testing.T) {
+	RegisterDialect("minimal", minimalDialect{})
+	RegisterDialect("numbered", numberedDialect{})
+	defer delete(dialectsMap, "minimal")
+	defer delete(dialectsMap, "numbered")
+
+	testData := map[string]string{
+		"minimal":  "SELECT * FROM `users` WHERE (id = ?) AND (deleted = FALSE) ORDER BY id LIMIT 10 FOR UPDATE;",
+		"numbered": "SELECT * FROM `users` WHERE (id = :1) AND (deleted = 0) ORDER BY id LIMIT 10 FOR UPDATE;",
+	}
+
+	for k, v := range testData {
+		d := newDialect(k)
+		b := &SQLBuilder{
+			dialect: d,
+			table:   "users",
+			order:   "id",
+			limit:   "10",
+			lock:    "UPDATE",
+		}
+		b.Where("id = ?", 1)
+		b.Where("deleted = " + d.BoolLiteral(false))
+
+		if query := b.queryString(); query != v {
+			t.Errorf("%s dialect query = %s, want %s", k, query, v)
+		}
+
+		if d.Supports(FeatureReturning) || !d.Supports(FeatureUpsert) {
+			t.Errorf("%s dialect features error", k)
+		}
+
+		if got := d.OnConflict([]string{"id"}, ""); got != "ON CONFLICT (`id`) DO NOTHING" {
+			t.Errorf("%s dialect on conflict error: %s", k, got)
+		}
+	}
+}
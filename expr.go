@@ -11,3 +11,8 @@ type expr struct {
 func Expr(expression string, args ...interface{}) *expr {
 	return &expr{expr: expression, args: args}
 }
+
+// Build implements Condition, so a raw SQL expression can be combined with the other conditions
+func (e *expr) Build(d Dialect) (string, []interface{}) {
+	return e.expr, e.args
+}
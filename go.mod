@@ -1,6 +1,6 @@
 module github.com/ilibs/gosql/v2
 
-go 1.13
+go 1.18
 
 require (
 	github.com/go-sql-driver/mysql v1.7.1
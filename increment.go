@@ -0,0 +1,120 @@
+package gosql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+)
+
+// Increment adds delta to the column by a single UPDATE statement, the AUTO_UPDATE_TIME_FIELDS are set to the current time,
+// the returning columns are scanned into the model after the update, for example:
+// gosql.Model(&Moments{Id: 1}).Returning("like_total").Increment("like_total", 1)
+func (b *Builder) Increment(column string, delta interface{}) (affected int64, err error) {
+	return b.increment(column, "+", delta)
+}
+
+// Decrement subtracts delta from the column by a single UPDATE statement, see Increment
+func (b *Builder) Decrement(column string, delta interface{}) (affected int64, err error) {
+	return b.increment(column, "-", delta)
+}
+
+func (b *Builder) increment(column, op string, delta interface{}) (affected int64, err error) {
+	b.initModel()
+	hook := NewHook(b.ctx, b.db)
+	hook.callMethod("BeforeChange", b.modelReflectValue)
+	hook.callMethod("BeforeUpdate", b.modelReflectValue)
+	if hook.HasError() {
+		return 0, hook.Error()
+	}
+
+	fields := b.reflectModel(nil)
+	// the update time is set even if the model was loaded with it
+	for _, name := range AUTO_UPDATE_TIME_FIELDS {
+		if v, ok := fields[name]; ok {
+			v = reflect.Indirect(v)
+			v.Set(reflect.Zero(v.Type()))
+		}
+	}
+	structAutoTime(fields, AUTO_UPDATE_TIME_FIELDS)
+
+	m := map[string]interface{}{
+		column: Expr(b.quoteColumn(column)+" "+op+" ?", delta),
+	}
+	for _, name := range AUTO_UPDATE_TIME_FIELDS {
+		if v, ok := fields[name]; ok && name != column {
+			m[name] = reflect.Indirect(v).Interface()
+		}
+	}
+
+	// If where is empty, the primary key where condition is generated automatically
+	pkScoped := b.where == ""
+	b.generateWhereForPK(zeroValueFilter(fields, nil))
+	pkScoped = pkScoped && b.where != ""
+	where, args := b.where, append([]interface{}{}, b.args...)
+
+	if len(b.returning) > 0 {
+		affected, err = b.incrementReturning(fields, m, where, args, pkScoped)
+	} else {
+		var result sql.Result
+		if result, err = b.db.ExecContext(b.context(), b.updateString(m), b.args...); err == nil {
+			affected, err = result.RowsAffected()
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	hook.callMethod("AfterUpdate", b.modelReflectValue)
+	hook.callMethod("AfterChange", b.modelReflectValue)
+
+	if hook.HasError() {
+		return 0, hook.Error()
+	}
+
+	return affected, nil
+}
+
+// incrementReturning updates the row by the primary key and scans the returning columns into the model by UPDATE ... RETURNING,
+// if the database does not support it, the row is selected again by the primary key in the same transaction,
+// so the updated row is locked and the value is not changed by the later updates
+func (b *Builder) incrementReturning(fields map[string]reflect.Value, m map[string]interface{}, where string, args []interface{}, pkScoped bool) (affected int64, err error) {
+	// the where condition may match many rows, but the returning columns are scanned into one model
+	if !pkScoped {
+		return 0, errors.New("returning needs the primary key of the model and no where condition")
+	}
+
+	dest, err := returningDest(fields, b.returning)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, ok := b.dialect.(outputDialect); !ok && b.dialect.Supports(FeatureReturning) {
+		err = b.db.queryRowPrimary(b.context(), dest, b.updateReturningString(m, b.returning), b.args...)
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		return 1, nil
+	}
+
+	update := func(ctx context.Context, db *DB) error {
+		result, err := db.ExecContext(ctx, b.updateString(m), b.args...)
+		if err != nil {
+			return err
+		}
+		if affected, err = result.RowsAffected(); err != nil || affected == 0 {
+			return err
+		}
+
+		s := &SQLBuilder{dialect: b.dialect, table: b.table, where: where, args: args}
+		return db.queryRowPrimary(ctx, dest, s.rebind(s.selectStatement(s.selectString(b.returningFields()))), s.args...)
+	}
+
+	if b.db.tx != nil {
+		return affected, update(b.context(), b.db)
+	}
+	return affected, b.db.txx(b.context(), nil, update)
+}
@@ -0,0 +1,58 @@
+package gosql
+
+import (
+	"testing"
+
+	"github.com/ilibs/gosql/v2/internal/example/models"
+)
+
+func TestBuilder_Increment(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		initDatas(t)
+
+		moment := &models.Moments{Id: 1}
+		if err := Model(moment).Get(); err != nil {
+			t.Fatal(err)
+		}
+		updatedAt := moment.UpdatedAt
+
+		affected, err := Model(&models.Moments{Id: 1}).Increment("like_total", 3)
+		if err != nil || affected != 1 {
+			t.Fatal("increment error", affected, err)
+		}
+
+		affected, err = Model(moment).Returning("like_total").Decrement("like_total", 1)
+		if err != nil || affected != 1 {
+			t.Fatal("decrement error", affected, err)
+		}
+
+		if moment.LikeTotal != 2 {
+			t.Error("returning column must be refreshed", moment.LikeTotal)
+		}
+
+		if !moment.UpdatedAt.After(updatedAt) {
+			t.Error("update time must be set", moment.UpdatedAt, updatedAt)
+		}
+
+		result := &models.Moments{Id: 1}
+		if err := Model(result).Get(); err != nil {
+			t.Fatal(err)
+		}
+
+		if result.LikeTotal != 2 || result.CommentTotal != moment.CommentTotal {
+			t.Errorf("increment only updates the column: %#v", result)
+		}
+
+		if _, err := Model(&models.Moments{}).Where("id = ?", 1).Returning("like_total").Increment("like_total", 1); err == nil {
+			t.Error("returning must be scoped by the primary key")
+		}
+
+		err = Tx(func(tx *DB) error {
+			_, err := tx.Model(moment).Returning("like_total").Increment("like_total", 1)
+			return err
+		})
+		if err != nil || moment.LikeTotal != 3 {
+			t.Error("increment in a transaction error", moment.LikeTotal, err)
+		}
+	})
+}
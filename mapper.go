@@ -1,30 +1,132 @@
 package gosql
 
+import (
+	"context"
+	"strconv"
+)
+
 type Mapper struct {
-	db *DB
+	db  *DB
+	ctx context.Context
 	SQLBuilder
 }
 
 // Table select table name
 func Table(t string) *Mapper {
-	db := &DB{database: Sqlx(defaultLink)}
+	db := Use(defaultLink)
 	return &Mapper{db: db, SQLBuilder: SQLBuilder{table: t, dialect: newDialect(db.DriverName())}}
 }
 
+// context returns the mapper context, or context.Background if it is not set
+func (m *Mapper) context() context.Context {
+	if m.ctx == nil {
+		return context.Background()
+	}
+	return m.ctx
+}
+
 func (m *Mapper) ShowSQL() *Mapper {
 	m.db.logging = true
 	return m
 }
 
+// UsePrimary read from the primary instead of the replicas
+func (m *Mapper) UsePrimary() *Mapper {
+	m.db = m.db.withPrimary()
+	return m
+}
+
 //Where
-func (m *Mapper) Where(str string, args ...interface{}) *Mapper {
-	m.SQLBuilder.Where(str, args...)
+func (m *Mapper) Where(query interface{}, args ...interface{}) *Mapper {
+	m.SQLBuilder.Where(query, args...)
+	return m
+}
+
+// OrWhere for example Where("status = ?", 1).OrWhere(gosql.In("id", []int{1, 2}))
+func (m *Mapper) OrWhere(query interface{}, args ...interface{}) *Mapper {
+	m.SQLBuilder.OrWhere(query, args...)
+	return m
+}
+
+// Join for example Join("users u", "u.id = m.user_id and u.status = ?", 1)
+func (m *Mapper) Join(table string, on string, args ...interface{}) *Mapper {
+	m.join("JOIN", table, on, args...)
+	return m
+}
+
+// LeftJoin for example LeftJoin("users u", "u.id = m.user_id")
+func (m *Mapper) LeftJoin(table string, on string, args ...interface{}) *Mapper {
+	m.join("LEFT JOIN", table, on, args...)
+	return m
+}
+
+// RightJoin for example RightJoin("users u", "u.id = m.user_id")
+func (m *Mapper) RightJoin(table string, on string, args ...interface{}) *Mapper {
+	m.join("RIGHT JOIN", table, on, args...)
+	return m
+}
+
+// InnerJoin for example InnerJoin("users u", "u.id = m.user_id")
+func (m *Mapper) InnerJoin(table string, on string, args ...interface{}) *Mapper {
+	m.join("INNER JOIN", table, on, args...)
+	return m
+}
+
+// Select filter column
+func (m *Mapper) Select(fields string) *Mapper {
+	m.fields = fields
+	return m
+}
+
+// GroupBy for example "user_id"
+func (m *Mapper) GroupBy(str string) *Mapper {
+	m.groupBy = str
+	return m
+}
+
+// Having for example Having("count(*) > ?", 1)
+func (m *Mapper) Having(str string, args ...interface{}) *Mapper {
+	m.SQLBuilder.Having(str, args...)
 	return m
 }
 
+// Distinct select distinct rows
+func (m *Mapper) Distinct() *Mapper {
+	m.distinct = true
+	return m
+}
+
+// Limit
+func (m *Mapper) Limit(i int) *Mapper {
+	m.limit = strconv.Itoa(i)
+	return m
+}
+
+// Offset
+func (m *Mapper) Offset(i int) *Mapper {
+	m.offset = strconv.Itoa(i)
+	return m
+}
+
+// OrderBy for example "id desc"
+func (m *Mapper) OrderBy(str string) *Mapper {
+	m.order = str
+	return m
+}
+
+// Get data row to dest, dest can be any struct, for example the result of join
+func (m *Mapper) Get(dest interface{}) error {
+	return m.db.GetContext(m.context(), dest, m.queryString(), m.queryArgs()...)
+}
+
+// All data rows to dest, dest can be any struct slice, for example the result of join
+func (m *Mapper) All(dest interface{}) error {
+	return m.db.SelectContext(m.context(), dest, m.queryString(), m.queryArgs()...)
+}
+
 //Update data from to map[string]interface
 func (m *Mapper) Update(data map[string]interface{}) (affected int64, err error) {
-	result, err := m.db.Exec(m.updateString(data), m.args...)
+	result, err := m.db.ExecContext(m.context(), m.updateString(data), m.args...)
 	if err != nil {
 		return 0, err
 	}
@@ -32,19 +134,50 @@ func (m *Mapper) Update(data map[string]interface{}) (affected int64, err error)
 	return result.RowsAffected()
 }
 
-//Create data from to map[string]interface
+//Create data from to map[string]interface, lastInsertId is 0 if the driver does not support it
 func (m *Mapper) Create(data map[string]interface{}) (lastInsertId int64, err error) {
-	result, err := m.db.Exec(m.insertString(data), m.args...)
+	result, err := m.db.ExecContext(m.context(), m.insertString(data), m.args...)
 	if err != nil {
 		return 0, err
 	}
 
+	if !m.dialect.Supports(FeatureLastInsertId) {
+		return 0, nil
+	}
+
 	return result.LastInsertId()
 }
 
+// Upsert insert data or update the row that conflicts on conflictColumns, the update values can be gosql.Expr,
+// if update is nil the columns of data except conflictColumns are updated to the inserted values
+// for example:
+// gosql.Table("users").Upsert(data, []string{"id"}, map[string]interface{}{"login_total": gosql.Expr("users.login_total + 1")})
+func (m *Mapper) Upsert(data map[string]interface{}, conflictColumns []string, update map[string]interface{}) (affected int64, err error) {
+	if err := m.upsertError(conflictColumns); err != nil {
+		return 0, err
+	}
+
+	if update == nil {
+		var columns []string
+		for _, k := range sortedParamKeys(data) {
+			if !inSlice(k, conflictColumns) {
+				columns = append(columns, k)
+			}
+		}
+		update = m.excludedUpdates(columns)
+	}
+
+	result, err := m.db.ExecContext(m.context(), m.upsertString(data, conflictColumns, update), m.args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
 //Delete data from to map[string]interface
 func (m *Mapper) Delete() (affected int64, err error) {
-	result, err := m.db.Exec(m.deleteString(), m.args...)
+	result, err := m.db.ExecContext(m.context(), m.deleteString(), m.args...)
 	if err != nil {
 		return 0, err
 	}
@@ -54,6 +187,6 @@ func (m *Mapper) Delete() (affected int64, err error) {
 
 //Count data from to map[string]interface
 func (m *Mapper) Count() (num int64, err error) {
-	err = m.db.Get(&num, m.countString(), m.args...)
+	err = m.db.GetContext(m.context(), &num, m.countString(), m.queryArgs()...)
 	return num, err
 }
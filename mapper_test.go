@@ -3,6 +3,7 @@ package gosql
 import (
 	"strconv"
 	"testing"
+	"time"
 )
 
 func mapInsert(t *testing.T, id int64) int64 {
@@ -49,6 +50,44 @@ func TestMapper_Update(t *testing.T) {
 	})
 }
 
+func TestMapper_Upsert(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		id := mapInsert(t, 1)
+
+		_, err := Table("users").Upsert(map[string]interface{}{
+			"id":         id,
+			"name":       "fifsky",
+			"status":     1,
+			"created_at": time.Now(),
+			"updated_at": time.Now(),
+		}, []string{"id"}, map[string]interface{}{
+			"status": Expr("users.status + ?", 1),
+		})
+
+		if err != nil {
+			t.Error(err)
+		}
+
+		var status int
+		if err := Table("users").Select("status").Where("id = ?", id).Get(&status); err != nil {
+			t.Fatal(err)
+		}
+
+		if status != 2 {
+			t.Error("map upsert error", status)
+		}
+
+		num, err := Table("users").Count()
+		if err != nil {
+			t.Error(err)
+		}
+
+		if num != 1 {
+			t.Error("map upsert must not insert", num)
+		}
+	})
+}
+
 func TestMapper_Delete(t *testing.T) {
 	RunWithSchema(t, func(t *testing.T) {
 		{
@@ -107,3 +146,84 @@ func TestMapper_Count(t *testing.T) {
 		}
 	})
 }
+
+func TestMapper_Join(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		initDatas(t)
+
+		type momentUser struct {
+			Id      int    `db:"id"`
+			Content string `db:"content"`
+			Name    string `db:"name"`
+		}
+
+		rows := make([]*momentUser, 0)
+		err := Table("moments m").Select("m.id, m.content, u.name").
+			InnerJoin("users u", "u.id = m.user_id").
+			Where("m.user_id = ?", 6).
+			OrderBy("m.id").
+			Limit(2).
+			All(&rows)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(rows) != 2 || rows[0].Id != 3 || rows[0].Name != "呵呵" {
+			t.Error("mapper join error")
+		}
+
+		row := &momentUser{}
+		err = Table("moments m").Select("m.id, m.content, u.name").
+			LeftJoin("users u", "u.id = m.user_id").
+			Where("m.id = ?", 14).
+			Get(row)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if row.Name != "呵呵" {
+			t.Error("mapper join get error")
+		}
+	})
+}
+
+func TestMapper_GroupBy(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		initDatas(t)
+
+		type userTotal struct {
+			UserId int `db:"user_id"`
+			Total  int `db:"total"`
+		}
+
+		rows := make([]*userTotal, 0)
+		err := Table("moments").Select("user_id, count(*) as total").Where("status = ?", 1).GroupBy("user_id").Having("count(*) > ?", 5).All(&rows)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(rows) != 1 || rows[0].UserId != 5 || rows[0].Total != 11 {
+			t.Error("group by having error")
+		}
+
+		num, err := Table("moments").GroupBy("user_id").Having("count(*) > ?", 5).Count()
+		if err != nil {
+			t.Error(err)
+		}
+
+		if num != 1 {
+			t.Error("group by count error", num)
+		}
+
+		num, err = Table("moments").Select("user_id").Distinct().Count()
+		if err != nil {
+			t.Error(err)
+		}
+
+		if num != 2 {
+			t.Error("distinct count error", num)
+		}
+	})
+}
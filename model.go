@@ -2,6 +2,7 @@ package gosql
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"reflect"
@@ -25,6 +26,13 @@ var (
 		"update_at",
 		"updated_at",
 	}
+	// Soft delete fields, Delete sets the first column that the model has instead of deleting the row,
+	// a nullable column is NULL, a bool column is false and a number column is 0 if the row is not deleted
+	SOFT_DELETE_FIELDS = []string{
+		"deleted_at",
+		"delete_time",
+		"is_deleted",
+	}
 )
 
 // Model interface
@@ -41,13 +49,20 @@ type Builder struct {
 	ctx               context.Context
 	SQLBuilder
 	modelWrapper *ModelWrapper
+	unscoped     bool
+	trashed      int
+	// scoped the soft delete scope has been applied
+	scoped        bool
+	cursorColumns []cursorColumn
+	cursorValues  []interface{}
+	cursorErr     error
 }
 
 // Model construct SQL from Struct
 func Model(model interface{}) *Builder {
 	return &Builder{
 		model: model,
-		db:    &DB{database: Sqlx(defaultLink)},
+		db:    Use(defaultLink),
 	}
 }
 
@@ -63,12 +78,33 @@ func WithContext(ctx context.Context) *Builder {
 	return &Builder{db: w, SQLBuilder: SQLBuilder{dialect: newDialect(w.DriverName())}, ctx: ctx}
 }
 
+// Table database handler from to table name with the builder context
+// for example:
+// gosql.WithContext(ctx).Table("users")
+func (b *Builder) Table(t string) *Mapper {
+	return &Mapper{db: b.db, ctx: b.ctx, SQLBuilder: SQLBuilder{table: t, dialect: newDialect(b.db.DriverName())}}
+}
+
+// context returns the builder context, or context.Background if it is not set
+func (b *Builder) context() context.Context {
+	if b.ctx == nil {
+		return context.Background()
+	}
+	return b.ctx
+}
+
 // ShowSQL output single sql
 func (b *Builder) ShowSQL() *Builder {
 	b.db.logging = true
 	return b
 }
 
+// UsePrimary read from the primary instead of the replicas
+func (b *Builder) UsePrimary() *Builder {
+	b.db = b.db.withPrimary()
+	return b
+}
+
 func (b *Builder) initModel() {
 	if b.model == nil {
 		log.Panicf("model argument must not nil")
@@ -130,15 +166,53 @@ func (b *Builder) Hint(hint string) *Builder {
 	return b
 }
 
-// ForceIndex
+// ForceIndex the index hint is rendered by the dialect, for example force index(i) of MySQL
+// and INDEXED BY i of SQLite, it is ignored by Postgres
 func (b *Builder) ForceIndex(i string) *Builder {
 	b.forceIndex = i
 	return b
 }
 
-// Where for example Where("id = ? and name = ?",1,"test")
-func (b *Builder) Where(str string, args ...interface{}) *Builder {
-	b.SQLBuilder.Where(str, args...)
+// OptimizerHint the hint is rendered by the dialect, for example OptimizerHint("MAX_EXECUTION_TIME(1000)")
+// is SELECT /*+ MAX_EXECUTION_TIME(1000) */ of MySQL
+func (b *Builder) OptimizerHint(hint string) *Builder {
+	b.optimizerHint = hint
+	return b
+}
+
+// Where for example Where("id = ? and name = ?",1,"test") or Where(gosql.Eq("id", 1))
+func (b *Builder) Where(query interface{}, args ...interface{}) *Builder {
+	b.SQLBuilder.Where(query, args...)
+	return b
+}
+
+// OrWhere for example Where("status = ?", 1).OrWhere(gosql.In("id", []int{1, 2}))
+func (b *Builder) OrWhere(query interface{}, args ...interface{}) *Builder {
+	b.SQLBuilder.OrWhere(query, args...)
+	return b
+}
+
+// Join for example Join("users u", "u.id = moments.user_id and u.status = ?", 1)
+func (b *Builder) Join(table string, on string, args ...interface{}) *Builder {
+	b.join("JOIN", table, on, args...)
+	return b
+}
+
+// LeftJoin for example LeftJoin("users u", "u.id = moments.user_id")
+func (b *Builder) LeftJoin(table string, on string, args ...interface{}) *Builder {
+	b.join("LEFT JOIN", table, on, args...)
+	return b
+}
+
+// RightJoin for example RightJoin("users u", "u.id = moments.user_id")
+func (b *Builder) RightJoin(table string, on string, args ...interface{}) *Builder {
+	b.join("RIGHT JOIN", table, on, args...)
+	return b
+}
+
+// InnerJoin for example InnerJoin("users u", "u.id = moments.user_id")
+func (b *Builder) InnerJoin(table string, on string, args ...interface{}) *Builder {
+	b.join("INNER JOIN", table, on, args...)
 	return b
 }
 
@@ -148,6 +222,82 @@ func (b *Builder) Select(fields string) *Builder {
 	return b
 }
 
+// GroupBy for example "user_id"
+func (b *Builder) GroupBy(str string) *Builder {
+	b.groupBy = str
+	return b
+}
+
+// Having for example Having("count(*) > ?", 1)
+func (b *Builder) Having(str string, args ...interface{}) *Builder {
+	b.SQLBuilder.Having(str, args...)
+	return b
+}
+
+// Distinct select distinct rows
+func (b *Builder) Distinct() *Builder {
+	b.distinct = true
+	return b
+}
+
+// Returning the columns generated by the database are scanned into the model after Create,
+// Increment and Decrement, for example Returning("created_at"), the primary key is always filled by Create
+func (b *Builder) Returning(columns ...string) *Builder {
+	b.returning = columns
+	return b
+}
+
+// ErrLockWithoutTx is returned when the row lock is used outside a transaction,
+// the lock would be released as soon as the query returns
+var ErrLockWithoutTx = errors.New("row lock must be used in a transaction")
+
+// ForUpdate lock the selected rows, for example:
+// tx.Model(&jobs).Where("status = ?", 0).Limit(10).ForUpdate().SkipLocked().All()
+func (b *Builder) ForUpdate() *Builder {
+	b.lock = "UPDATE"
+	return b
+}
+
+// ForShare lock the selected rows in share mode
+func (b *Builder) ForShare() *Builder {
+	b.lock = "SHARE"
+	return b
+}
+
+// NoWait return an error instead of waiting for the locked rows, it implies ForUpdate if no lock is set
+func (b *Builder) NoWait() *Builder {
+	b.lockOption = "NOWAIT"
+	if b.lock == "" {
+		b.lock = "UPDATE"
+	}
+	return b
+}
+
+// SkipLocked skip the locked rows, it implies ForUpdate if no lock is set
+func (b *Builder) SkipLocked() *Builder {
+	b.lockOption = "SKIP LOCKED"
+	if b.lock == "" {
+		b.lock = "UPDATE"
+	}
+	return b
+}
+
+// lockError checks the row lock can be used
+func (b *Builder) lockError() error {
+	if b.lock == "" {
+		return nil
+	}
+
+	if !b.dialect.Supports(FeatureRowLock) {
+		return unsupported(b.dialect, "row lock")
+	}
+
+	if b.db.tx == nil {
+		return ErrLockWithoutTx
+	}
+	return nil
+}
+
 // Limit
 func (b *Builder) Limit(i int) *Builder {
 	b.limit = strconv.Itoa(i)
@@ -186,29 +336,60 @@ func (b *Builder) Relation(fieldName string, fn BuilderChainFunc) *Builder {
 // All get data row from to Struct
 func (b *Builder) Get(zeroValues ...string) (err error) {
 	b.initModel()
+	if err := b.lockError(); err != nil {
+		return err
+	}
+
 	m := zeroValueFilter(b.reflectModel(nil), zeroValues)
 	// If where is empty, the primary key where condition is generated automatically
 	b.generateWhere(m)
+	b.softDeleteScope()
 
 	if b.modelWrapper != nil {
-		return b.db.Get(b.modelWrapper, b.queryString(), b.args...)
+		err = b.db.GetContext(b.context(), b.modelWrapper, b.queryString(), b.queryArgs()...)
+	} else {
+		err = b.db.GetContext(b.context(), b.model, b.queryString(), b.queryArgs()...)
+	}
+
+	if err == nil {
+		snapshot(b.model)
 	}
-	return b.db.Get(b.model, b.queryString(), b.args...)
+	return err
 }
 
 // All get data rows from to Struct
 func (b *Builder) All() (err error) {
 	b.initModel()
+	if err := b.lockError(); err != nil {
+		return err
+	}
+	b.softDeleteScope()
+	if err := b.cursorScope(); err != nil {
+		return err
+	}
 
 	if b.modelWrapper != nil {
-		return b.db.Select(b.modelWrapper, b.queryString(), b.args...)
+		err = b.db.SelectContext(b.context(), b.modelWrapper, b.queryString(), b.queryArgs()...)
+	} else {
+		err = b.db.SelectContext(b.context(), b.model, b.queryString(), b.queryArgs()...)
 	}
-	return b.db.Select(b.model, b.queryString(), b.args...)
+
+	if err == nil {
+		snapshot(b.model)
+	}
+	return err
 }
 
-// Create data from to Struct
+// Create data from to Struct, a slice model is inserted by a single multi-row statement
+// for example:
+// gosql.Model(&[]*Users{{Name: "a"}, {Name: "b"}}).Create()
 func (b *Builder) Create() (lastInsertId int64, err error) {
 	b.initModel()
+	if rows, ok := b.modelSlice(); ok {
+		lastInsertId, _, err = b.createRows(rows, 0)
+		return lastInsertId, err
+	}
+
 	hook := NewHook(b.ctx, b.db)
 	hook.callMethod("BeforeChange", b.modelReflectValue)
 	hook.callMethod("BeforeCreate", b.modelReflectValue)
@@ -219,7 +400,18 @@ func (b *Builder) Create() (lastInsertId int64, err error) {
 	fields := b.reflectModel(AUTO_CREATE_TIME_FIELDS)
 	m := structToMap(fields)
 
-	result, err := b.db.Exec(b.insertString(m), b.args...)
+	switch {
+	case b.dialect.Supports(FeatureReturning) && (len(b.returning) > 0 || !b.dialect.Supports(FeatureLastInsertId)):
+		lastInsertId, err = b.createReturning(fields, m)
+	case b.dialect.Supports(FeatureLastInsertId):
+		lastInsertId, err = b.createExec(fields, m)
+	case len(b.returning) > 0:
+		err = unsupported(b.dialect, "returning")
+	default:
+		// the database does not generate the primary key, such as ClickHouse
+		_, err = b.db.ExecContext(b.context(), b.insertString(m), b.args...)
+	}
+
 	if err != nil {
 		return 0, err
 	}
@@ -231,22 +423,320 @@ func (b *Builder) Create() (lastInsertId int64, err error) {
 		return 0, hook.Error()
 	}
 
-	lastId, err := result.LastInsertId()
+	return lastInsertId, nil
+}
+
+// createExec insert the model and fill the primary key by LastInsertId,
+// the returning columns are selected again by the primary key
+func (b *Builder) createExec(fields map[string]reflect.Value, m map[string]interface{}) (lastInsertId int64, err error) {
+	result, err := b.db.ExecContext(b.context(), b.insertString(m), b.args...)
+	if err != nil {
+		return 0, err
+	}
 
+	lastId, err := result.LastInsertId()
 	if err != nil {
 		return 0, err
 	}
 
-	if v, ok := fields[b.modelEntity.PK()]; ok {
+	pk := b.modelEntity.PK()
+	v, ok := fields[pk]
+	if ok {
 		fillPrimaryKey(v, lastId)
 	}
 
-	return lastId, err
+	if len(b.returning) > 0 && ok {
+		dest, err := returningDest(fields, b.returning)
+		if err != nil {
+			return 0, err
+		}
+
+		query := b.rebind(fmt.Sprintf("SELECT %s FROM %s WHERE %s=?%s", b.returningFields(), b.dialect.Quote(b.table), b.dialect.Quote(pk), b.dialect.Terminator()))
+		if err := b.db.queryRowPrimary(b.context(), dest, query, reflect.Indirect(v).Interface()); err != nil {
+			return 0, err
+		}
+	}
+
+	return lastId, nil
+}
+
+// createReturning insert the model by INSERT ... RETURNING, a zero primary key is generated by the database
+func (b *Builder) createReturning(fields map[string]reflect.Value, m map[string]interface{}) (lastInsertId int64, err error) {
+	pk := b.modelEntity.PK()
+	columns := b.returning
+	v, ok := fields[pk]
+	if ok && IsZero(reflect.Indirect(v)) {
+		delete(m, pk)
+		if !inSlice(pk, columns) {
+			columns = append([]string{pk}, columns...)
+		}
+	}
+
+	if len(columns) == 0 {
+		if _, err := b.db.ExecContext(b.context(), b.insertString(m), b.args...); err != nil {
+			return 0, err
+		}
+		return fieldInt(v), nil
+	}
+
+	dest, err := returningDest(fields, columns)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := b.db.queryRowPrimary(b.context(), dest, b.insertReturningString(m, columns), b.args...); err != nil {
+		return 0, err
+	}
+
+	if ok {
+		return fieldInt(v), nil
+	}
+	return 0, nil
+}
+
+// returningDest returns the pointers of the fields that the returned columns are scanned into
+func returningDest(fields map[string]reflect.Value, columns []string) ([]interface{}, error) {
+	dest := make([]interface{}, len(columns))
+	for i, col := range columns {
+		v, ok := fields[col]
+		if !ok {
+			return nil, fmt.Errorf("returning column `%s` is not a field of the model", col)
+		}
+		dest[i] = v.Addr().Interface()
+	}
+	return dest, nil
+}
+
+// CreateInBatches insert the slice model by multi-row statements of size rows,
+// the batches are not wrapped in a transaction, use gosql.Tx if it is needed
+func (b *Builder) CreateInBatches(size int) (affected int64, err error) {
+	b.initModel()
+	rows, ok := b.modelSlice()
+	if !ok {
+		log.Panicf("CreateInBatches model argument must be a slice, but get %#v", b.model)
+	}
+
+	_, affected, err = b.createRows(rows, size)
+	return affected, err
+}
+
+// modelSlice returns the slice of the model, ok is false if the model is a single struct
+func (b *Builder) modelSlice() (rows reflect.Value, ok bool) {
+	if _, ok := b.model.(IModel); ok {
+		return reflect.Value{}, false
+	}
+
+	rows = reflect.Indirect(reflect.ValueOf(b.model))
+	if rows.Kind() == reflect.Interface {
+		rows = rows.Elem()
+	}
+	return rows, rows.Kind() == reflect.Slice
 }
 
+// createRows insert the rows by statements of size rows, size <= 0 insert all rows by one statement
+func (b *Builder) createRows(rows reflect.Value, size int) (lastInsertId int64, affected int64, err error) {
+	n := rows.Len()
+	if n == 0 {
+		return 0, 0, nil
+	}
+
+	values := make([]reflect.Value, n)
+	for i := range values {
+		values[i] = rows.Index(i)
+		if values[i].Kind() != reflect.Ptr {
+			values[i] = values[i].Addr()
+		}
+	}
+
+	hook := NewHook(b.ctx, b.db)
+	for _, v := range values {
+		hook.callMethod("BeforeChange", v)
+		hook.callMethod("BeforeCreate", v)
+	}
+	if hook.HasError() {
+		return 0, 0, hook.Error()
+	}
+
+	// the primary keys are generated by the database only if no row has one
+	pk := b.modelEntity.PK()
+	autoPK := true
+	fields := make([]map[string]reflect.Value, n)
+	params := make([]map[string]interface{}, n)
+	for i, v := range values {
+		fields[i] = mapper.FieldMap(v)
+		structAutoTime(fields[i], AUTO_CREATE_TIME_FIELDS)
+		if f, ok := fields[i][pk]; !ok || !IsZero(reflect.Indirect(f)) {
+			autoPK = false
+		}
+	}
+
+	for i := range fields {
+		params[i] = structToMap(fields[i])
+		if autoPK {
+			delete(params[i], pk)
+		}
+	}
+	columns := sortedParamKeys(params[0])
+
+	if size <= 0 || size > n {
+		size = n
+	}
+
+	for start := 0; start < n; start += size {
+		end := start + size
+		if end > n {
+			end = n
+		}
+
+		b.args = nil
+		// the primary keys are returned by RETURNING if the driver does not support LastInsertId
+		if autoPK && !b.dialect.Supports(FeatureLastInsertId) && b.dialect.Supports(FeatureReturning) {
+			num, err := b.createRowsReturning(columns, params[start:end], fields[start:end])
+			affected += num
+			if err != nil {
+				return lastInsertId, affected, err
+			}
+			if num > 0 {
+				lastInsertId = fieldInt(fields[start+int(num)-1][pk])
+			}
+			continue
+		}
+
+		result, err := b.db.ExecContext(b.context(), b.insertRowsString(columns, params[start:end]), b.args...)
+		if err != nil {
+			return lastInsertId, affected, err
+		}
+
+		num, err := result.RowsAffected()
+		if err != nil {
+			return lastInsertId, affected, err
+		}
+		affected += num
+
+		if !autoPK || !b.dialect.Supports(FeatureLastInsertId) {
+			continue
+		}
+
+		// some drivers do not support LastInsertId, the primary keys are not filled
+		if lastId, err := result.LastInsertId(); err == nil {
+			lastInsertId = lastId
+			if ids := insertedIds(b.dialect, lastId, end-start); ids != nil {
+				for i, id := range ids {
+					fillPrimaryKey(fields[start+i][pk], id)
+				}
+			}
+		}
+	}
+
+	for _, v := range values {
+		hook.callMethod("AfterCreate", v)
+		hook.callMethod("AfterChange", v)
+	}
+	if hook.HasError() {
+		return lastInsertId, affected, hook.Error()
+	}
+
+	return lastInsertId, affected, nil
+}
+
+// createRowsReturning inserts the rows by INSERT ... RETURNING pk and fills the primary keys in row order
+func (b *Builder) createRowsReturning(columns []string, params []map[string]interface{}, fields []map[string]reflect.Value) (affected int64, err error) {
+	pk := b.modelEntity.PK()
+	rows, err := b.db.queryPrimary(b.context(), b.insertRowsReturningString(columns, params, []string{pk}), b.args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() && int(affected) < len(fields) {
+		if err := rows.Scan(fields[affected][pk].Addr().Interface()); err != nil {
+			return affected, err
+		}
+		affected++
+	}
+	return affected, rows.Err()
+}
+
+// insertedIds returns the auto increment ids of the rows inserted by a multi-row statement,
+// MySQL reports the first id of the statement and SQLite reports the last one,
+// nil is returned if the ids cannot be derived from the dialect
+func insertedIds(d Dialect, lastId int64, n int) []int64 {
+	var first int64
+	switch d.GetName() {
+	case "mysql":
+		first = lastId
+	case "sqlite3":
+		first = lastId - int64(n) + 1
+	default:
+		return nil
+	}
+
+	ids := make([]int64, n)
+	for i := range ids {
+		ids[i] = first + int64(i)
+	}
+	return ids
+}
+
+// Upsert insert the model or update the row that conflicts on conflictColumns,
+// if updateColumns is empty the columns except the primary key, conflictColumns and create time are updated,
+// the update time columns are always updated
+// for example:
+// gosql.Model(&Users{Id: 1, Name: "test"}).Upsert([]string{"id"}, "name")
+func (b *Builder) Upsert(conflictColumns []string, updateColumns ...string) (affected int64, err error) {
+	b.initModel()
+	if err := b.upsertError(conflictColumns); err != nil {
+		return 0, err
+	}
+
+	hook := NewHook(b.ctx, b.db)
+	hook.callMethod("BeforeChange", b.modelReflectValue)
+	hook.callMethod("BeforeCreate", b.modelReflectValue)
+	if hook.HasError() {
+		return 0, hook.Error()
+	}
+
+	fields := b.reflectModel(AUTO_CREATE_TIME_FIELDS)
+	m := structToMap(fields)
+	// a zero primary key is generated by the database, otherwise the new rows conflict on it
+	if v, ok := fields[b.modelEntity.PK()]; ok && IsZero(reflect.Indirect(v)) {
+		delete(m, b.modelEntity.PK())
+	}
+
+	var columns []string
+	for _, k := range sortedParamKeys(m) {
+		if inSlice(k, AUTO_UPDATE_TIME_FIELDS) {
+			if !inSlice(k, updateColumns) {
+				columns = append(columns, k)
+			}
+			continue
+		}
+
+		if len(updateColumns) == 0 && k != b.modelEntity.PK() && !inSlice(k, conflictColumns) && !inSlice(k, AUTO_CREATE_TIME_FIELDS) {
+			columns = append(columns, k)
+		}
+	}
+	columns = append(columns, updateColumns...)
+
+	result, err := b.db.ExecContext(b.context(), b.upsertString(m, conflictColumns, b.excludedUpdates(columns)), b.args...)
+	if err != nil {
+		return 0, err
+	}
+
+	hook.callMethod("AfterCreate", b.modelReflectValue)
+	hook.callMethod("AfterChange", b.modelReflectValue)
+
+	if hook.HasError() {
+		return 0, hook.Error()
+	}
+
+	return result.RowsAffected()
+}
+
+// generateWhere the model fields are added to the whole where clause, so they also apply to the OrWhere conditions
 func (b *Builder) generateWhere(m map[string]interface{}) {
-	for k, v := range m {
-		b.Where(fmt.Sprintf("%s=%s", k, b.dialect.Placeholder()), v)
+	for _, k := range sortedParamKeys(m) {
+		b.scope(fmt.Sprintf("%s=?", b.qualifiedColumn(k)), m[k])
 	}
 }
 
@@ -254,7 +744,7 @@ func (b *Builder) generateWhereForPK(m map[string]interface{}) {
 	pk := b.modelEntity.PK()
 	pval, has := m[pk]
 	if b.where == "" && has {
-		b.Where(fmt.Sprintf("%s=%s", pk, b.dialect.Placeholder()), pval)
+		b.Where(fmt.Sprintf("%s=?", b.qualifiedColumn(pk)), pval)
 		delete(m, pk)
 	}
 }
@@ -270,16 +760,43 @@ func (b *Builder) Update(zeroValues ...string) (affected int64, err error) {
 	}
 
 	fields := b.reflectModel(AUTO_UPDATE_TIME_FIELDS)
-	m := zeroValueFilter(fields, zeroValues)
+	// the tracked model only writes the columns changed since it was loaded
+	m, tracked := changedValues(b.modelReflectValue.Interface(), b.modelEntity.PK(), fields, zeroValues)
+	if !tracked {
+		m = zeroValueFilter(fields, zeroValues)
+	}
 
 	// If where is empty, the primary key where condition is generated automatically
 	b.generateWhereForPK(m)
+	if tracked && len(m) == 0 {
+		return 0, nil
+	}
 
-	result, err := b.db.Exec(b.updateString(m), b.args...)
+	// the model with a version column is updated only if the row has not been changed since it was read
+	column, version := b.versionField()
+	if column != "" {
+		b.versionScope(column, version, m)
+	}
+
+	result, err := b.db.ExecContext(b.context(), b.updateString(m), b.args...)
 	if err != nil {
 		return 0, err
 	}
 
+	if column != "" {
+		if affected, err = result.RowsAffected(); err != nil {
+			return 0, err
+		}
+		if affected == 0 {
+			return 0, staleObject(b.table, fieldInt(version))
+		}
+		bumpVersion(version)
+	}
+
+	if tracked {
+		snapshot(b.modelReflectValue.Interface())
+	}
+
 	hook.callMethod("AfterUpdate", b.modelReflectValue)
 	hook.callMethod("AfterChange", b.modelReflectValue)
 
@@ -304,7 +821,14 @@ func (b *Builder) Delete(zeroValues ...string) (affected int64, err error) {
 	// If where is empty, the primary key where condition is generated automatically
 	b.generateWhere(m)
 
-	result, err := b.db.Exec(b.deleteString(), b.args...)
+	query := b.deleteString()
+	// the model with a soft delete column is marked deleted, Unscoped deletes the rows
+	if column, field := b.softDeleteField(); column != "" && !b.unscoped {
+		b.softDeleteScope()
+		query = b.updateString(map[string]interface{}{column: deletedValue(column, field.Type())})
+	}
+
+	result, err := b.db.ExecContext(b.context(), query, b.args...)
 	if err != nil {
 		return 0, err
 	}
@@ -326,7 +850,8 @@ func (b *Builder) Count(zeroValues ...string) (num int64, err error) {
 	m := zeroValueFilter(b.reflectModel(nil), zeroValues)
 	// If where is empty, the primary key where condition is generated automatically
 	b.generateWhere(m)
+	b.softDeleteScope()
 
-	err = b.db.Get(&num, b.countString(), b.args...)
+	err = b.db.GetContext(b.context(), &num, b.countString(), b.queryArgs()...)
 	return num, err
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"reflect"
 	"strconv"
 	"testing"
 	"time"
@@ -38,6 +39,17 @@ CREATE TABLE users (
   updated_at datetime NOT NULL,
   PRIMARY KEY (id)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+`,
+		"posts": `
+CREATE TABLE posts (
+  id int(11) unsigned NOT NULL AUTO_INCREMENT,
+  title varchar(255) NOT NULL DEFAULT '',
+  deleted_at datetime,
+  version int(11) unsigned NOT NULL DEFAULT '0',
+  created_at datetime NOT NULL,
+  updated_at datetime NOT NULL,
+  PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 `,
 		"photos": `
 CREATE TABLE photos (
@@ -357,9 +369,52 @@ func TestBuilder_Delete(t *testing.T) {
 				t.Error("delete user affected error", err)
 			}
 		}
+
+		{
+			insertStatus(1, 1)
+			insertStatus(2, 1)
+			insertStatus(3, 2)
+
+			// the primary key applies to both OR conditions
+			affected, err := Model(&models.Users{Id: 3}).Where("status = ?", 1).OrWhere("status = ?", 2).Delete()
+
+			if err != nil {
+				t.Error("delete user error", err)
+			}
+
+			if affected != 1 {
+				t.Error("delete user with OrWhere affected error", affected)
+			}
+
+			num, err := Model(&models.Users{}).Count()
+			if err != nil || num != 2 {
+				t.Error("delete user with OrWhere must keep the other rows", num, err)
+			}
+		}
 	})
 }
 
+func TestBuilder_generateWhere(t *testing.T) {
+	b := &Builder{SQLBuilder: SQLBuilder{dialect: mustGetDialect("mysql"), table: "users"}}
+	b.Where("status = ?", 1).OrWhere("status = ?", 2)
+	b.generateWhere(map[string]interface{}{"id": 5, "name": "test"})
+
+	if query := b.deleteString(); query != "DELETE FROM `users` WHERE ((status = ?) OR (status = ?)) AND (`id`=?) AND (`name`=?);" {
+		t.Error("generate where with OrWhere error", query)
+	}
+
+	if !reflect.DeepEqual(b.args, []interface{}{1, 2, 5, "test"}) {
+		t.Error("generate where args error", b.args)
+	}
+
+	b = &Builder{SQLBuilder: SQLBuilder{dialect: mustGetDialect("mysql"), table: "users"}}
+	b.Join("moments m", "m.user_id = users.id")
+	b.generateWhere(map[string]interface{}{"id": 5})
+	if query := b.countString(); query != "SELECT count(*) FROM `users` JOIN `moments` AS `m` ON m.user_id = users.id WHERE (`users`.`id`=?);" {
+		t.Error("generate where with join error", query)
+	}
+}
+
 func TestBuilder_Count(t *testing.T) {
 	RunWithSchema(t, func(t *testing.T) {
 		insert(1)
@@ -414,6 +469,209 @@ func TestBuilder_Create(t *testing.T) {
 	})
 }
 
+func TestBuilder_CreateReturning(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		insert(1)
+
+		user := &models.Users{Name: "test2"}
+		id, err := Model(user).Returning("success_time").Create()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if id != 2 || user.Id != 2 {
+			t.Error("fill primaryKey error", id, user.Id)
+		}
+
+		if user.SuccessTime.Valid {
+			t.Error("returning column error", user.SuccessTime)
+		}
+
+		if _, err := Model(&models.Users{Name: "test3"}).Returning("email").Create(); err == nil {
+			t.Error("returning column that is not a field must return an error")
+		}
+	})
+}
+
+func TestBuilder_CreateSlice(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		users := []*hookUser{
+			{Users: models.Users{Name: "test1", Status: 1}},
+			{Users: models.Users{Name: "test2", Status: 1}},
+			{Users: models.Users{Name: "test3", Status: 1}},
+		}
+
+		id, err := Model(&users).Create()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if id != 1 {
+			t.Error("lastInsertId error", id)
+		}
+
+		for i, u := range users {
+			if u.Id != i+1 {
+				t.Error("fill primaryKey error", u.Id)
+			}
+
+			if u.CreatedAt.IsZero() {
+				t.Error("auto create time error")
+			}
+		}
+
+		num, err := Model(&models.Users{}).Count()
+		if err != nil {
+			t.Error(err)
+		}
+
+		if num != 3 {
+			t.Error("create slice count error", num)
+		}
+	})
+}
+
+func TestBuilder_CreateInBatches(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		users := make([]models.Users, 5)
+		for i := range users {
+			users[i].Name = fmt.Sprintf("test%d", i)
+		}
+
+		affected, err := Model(&users).CreateInBatches(2)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if affected != 5 {
+			t.Error("create in batches affected error", affected)
+		}
+
+		if users[4].Id != 5 {
+			t.Error("fill primaryKey error", users[4].Id)
+		}
+
+		// BeforeCreate returns an error if the id is 1, nothing is inserted
+		hookUsers := []*hookUser{
+			{Users: models.Users{Id: 10, Name: "test10"}},
+			{Users: models.Users{Id: 1, Name: "test1"}},
+		}
+		if _, err := Model(&hookUsers).CreateInBatches(1); err == nil {
+			t.Error("before create hook error must be returned")
+		}
+
+		num, err := Model(&models.Users{}).Count()
+		if err != nil {
+			t.Error(err)
+		}
+
+		if num != 5 {
+			t.Error("create in batches count error", num)
+		}
+	})
+}
+
+func TestBuilder_Upsert(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		user := &models.Users{Id: 1, Name: "test", Status: 1}
+		if _, err := Model(user).Upsert([]string{"id"}); err != nil {
+			t.Fatal(err)
+		}
+
+		createdAt := user.CreatedAt
+		user = &models.Users{Id: 1, Name: "test2", Status: 2, CreatedAt: createdAt.Add(-time.Hour)}
+		if _, err := Model(user).Upsert([]string{"id"}, "name"); err != nil {
+			t.Fatal(err)
+		}
+
+		user = &models.Users{Id: 1}
+		if err := Model(user).Get(); err != nil {
+			t.Fatal(err)
+		}
+
+		if user.Name != "test2" {
+			t.Error("upsert update column error", user.Name)
+		}
+
+		if user.Status != 1 {
+			t.Error("upsert must only update the update columns", user.Status)
+		}
+
+		if !user.CreatedAt.Equal(createdAt) {
+			t.Error("upsert must not update the create time", user.CreatedAt)
+		}
+
+		num, err := Model(&models.Users{}).Count()
+		if err != nil {
+			t.Error(err)
+		}
+
+		if num != 1 {
+			t.Error("upsert count error", num)
+		}
+
+		// the rows without primary key are inserted
+		for _, name := range []string{"test3", "test4"} {
+			if _, err := Model(&models.Users{Name: name, Status: 1}).Upsert([]string{"id"}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		num, err = Model(&models.Users{}).Count()
+		if err != nil {
+			t.Error(err)
+		}
+
+		if num != 3 {
+			t.Error("upsert without primary key count error", num)
+		}
+	})
+}
+
+func TestBuilder_ForUpdate(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		insert(1)
+		insert(2)
+
+		users := make([]*models.Users, 0)
+		if err := Model(&users).ForUpdate().All(); err != ErrLockWithoutTx {
+			t.Error("lock without transaction must return ErrLockWithoutTx", err)
+		}
+
+		err := Tx(func(tx *DB) error {
+			users := make([]*models.Users, 0)
+			if err := tx.Model(&users).Where("status = ?", 1).OrderBy("id").Limit(1).ForUpdate().SkipLocked().All(); err != nil {
+				return err
+			}
+
+			if len(users) != 1 {
+				t.Error("lock rows error", len(users))
+			}
+
+			user := &models.Users{Id: 2}
+			return tx.Model(user).ForShare().NoWait().Get()
+		})
+
+		if err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func Test_insertedIds(t *testing.T) {
+	if ids := fmt.Sprint(insertedIds(mustGetDialect("mysql"), 10, 3)); ids != "[10 11 12]" {
+		t.Error("mysql inserted ids error", ids)
+	}
+
+	if ids := fmt.Sprint(insertedIds(mustGetDialect("sqlite3"), 12, 3)); ids != "[10 11 12]" {
+		t.Error("sqlite3 inserted ids error", ids)
+	}
+
+	if ids := insertedIds(mustGetDialect("postgres"), 12, 3); ids != nil {
+		t.Error("postgres inserted ids must be nil", ids)
+	}
+}
+
 func TestBuilder_Limit(t *testing.T) {
 	RunWithSchema(t, func(t *testing.T) {
 		insert(1)
@@ -566,3 +824,35 @@ func TestBuilder_Relation2(t *testing.T) {
 		}
 	})
 }
+
+func TestBuilder_Join(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		initDatas(t)
+
+		moments := make([]*models.Moments, 0)
+		err := Model(&moments).Join("users u", "u.id = moments.user_id and u.name = ?", "呵呵").Where("moments.status = ?", 1).All()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(moments) != 5 {
+			t.Error("join query error", len(moments))
+		}
+
+		for _, m := range moments {
+			if m.UserId != 6 {
+				t.Error("join query error, user_id must 6")
+			}
+		}
+
+		num, err := Model(&models.Moments{}).LeftJoin("users u", "u.id = moments.user_id").Where("u.name = ?", "呵呵").Count()
+		if err != nil {
+			t.Error(err)
+		}
+
+		if num != 5 {
+			t.Error("join count error", num)
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package gosql
+
+import (
+	"errors"
+)
+
+// ErrPerPage is returned by Paginate when perPage is less than 1
+var ErrPerPage = errors.New("perPage must be greater than 0")
+
+// Pagination the page metadata returned by Paginate
+type Pagination struct {
+	Total    int64 `json:"total"`
+	Page     int   `json:"page"`
+	PerPage  int   `json:"per_page"`
+	LastPage int   `json:"last_page"`
+	HasMore  bool  `json:"has_more"`
+}
+
+// newPagination the page less than 1 is the first page, the metadata is calculated from total
+func newPagination(page, perPage int, total int64) Pagination {
+	if page < 1 {
+		page = 1
+	}
+
+	lastPage := int((total + int64(perPage) - 1) / int64(perPage))
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	return Pagination{
+		Total:    total,
+		Page:     page,
+		PerPage:  perPage,
+		LastPage: lastPage,
+		HasMore:  page < lastPage,
+	}
+}
+
+// offset the offset of the first row of the page
+func (p Pagination) offset() int {
+	return (p.Page - 1) * p.PerPage
+}
+
+// Paginate counts the rows and selects the rows of the page with the same conditions, page starts at 1
+// var moments []*Moments
+// p, err := gosql.Model(&moments).Where("status = 1").OrderBy("id desc").Paginate(2, 20)
+func (b *Builder) Paginate(page, perPage int) (p Pagination, err error) {
+	if perPage < 1 {
+		return p, ErrPerPage
+	}
+
+	// the count statement has no ORDER BY and LIMIT
+	total, err := b.Count()
+	if err != nil {
+		return p, err
+	}
+
+	p = newPagination(page, perPage, total)
+	if int64(p.offset()) >= total {
+		return p, nil
+	}
+
+	return p, b.Limit(p.PerPage).Offset(p.offset()).All()
+}
+
+// Paginate counts the rows and selects the rows of the page to dest with the same conditions, page starts at 1
+// var users []*Users
+// p, err := gosql.Table("users").Where("status = 1").OrderBy("id desc").Paginate(&users, 2, 20)
+func (m *Mapper) Paginate(dest interface{}, page, perPage int) (p Pagination, err error) {
+	if perPage < 1 {
+		return p, ErrPerPage
+	}
+
+	total, err := m.Count()
+	if err != nil {
+		return p, err
+	}
+
+	p = newPagination(page, perPage, total)
+	if int64(p.offset()) >= total {
+		return p, nil
+	}
+
+	return p, m.Limit(p.PerPage).Offset(p.offset()).All(dest)
+}
@@ -0,0 +1,70 @@
+package gosql
+
+import (
+	"testing"
+
+	"github.com/ilibs/gosql/v2/internal/example/models"
+)
+
+func Test_newPagination(t *testing.T) {
+	tests := []struct {
+		page    int
+		perPage int
+		total   int64
+		want    Pagination
+	}{
+		{1, 10, 0, Pagination{Total: 0, Page: 1, PerPage: 10, LastPage: 1, HasMore: false}},
+		{0, 10, 25, Pagination{Total: 25, Page: 1, PerPage: 10, LastPage: 3, HasMore: true}},
+		{3, 10, 25, Pagination{Total: 25, Page: 3, PerPage: 10, LastPage: 3, HasMore: false}},
+		{2, 10, 20, Pagination{Total: 20, Page: 2, PerPage: 10, LastPage: 2, HasMore: false}},
+	}
+
+	for _, tt := range tests {
+		if got := newPagination(tt.page, tt.perPage, tt.total); got != tt.want {
+			t.Errorf("newPagination(%d, %d, %d) = %+v, want %+v", tt.page, tt.perPage, tt.total, got, tt.want)
+		}
+	}
+
+	if offset := newPagination(3, 10, 25).offset(); offset != 20 {
+		t.Error("offset error", offset)
+	}
+}
+
+func TestBuilder_Paginate(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		initDatas(t)
+
+		total, err := Model(&models.Moments{}).Where("status = 1").Count()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var moments []*models.Moments
+		p, err := Model(&moments).Where("status = 1").OrderBy("id desc").Paginate(2, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if p.Total != total || p.Page != 2 || p.PerPage != 5 || p.LastPage != int((total+4)/5) {
+			t.Errorf("pagination error: %+v", p)
+		}
+
+		if len(moments) != 5 {
+			t.Error("page rows error", len(moments))
+		}
+
+		var users []*models.Users
+		p, err = Table("users").Where("status = ?", 1).OrderBy("id desc").Paginate(&users, 100, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if p.HasMore || len(users) != 0 {
+			t.Errorf("the page after the last page must be empty: %+v %d", p, len(users))
+		}
+
+		if _, err := Model(&moments).Paginate(1, 0); err != ErrPerPage {
+			t.Error("per page error", err)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package gosql
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -41,7 +42,7 @@ func newModel(value reflect.Value, connection string) *Builder {
 	return m
 }
 
-func newModelWithWrapper(wrapper *ModelWrapper, defaultDb *DB, value reflect.Value, connection string) *Builder {
+func newModelWithWrapper(ctx context.Context, wrapper *ModelWrapper, defaultDb *DB, value reflect.Value, connection string) *Builder {
 	var m *Builder
 	if connection != "" {
 		var relationDb *DB
@@ -56,11 +57,17 @@ func newModelWithWrapper(wrapper *ModelWrapper, defaultDb *DB, value reflect.Val
 		// if connection is null,so db is default link
 		m = defaultDb.Model(value.Interface())
 	}
+	m.ctx = ctx
 	return m
 }
 
 // RelationOne is get the associated relational data for a single piece of data
 func RelationOne(wrapper *ModelWrapper, db *DB, data interface{}) error {
+	return RelationOneContext(context.Background(), wrapper, db, data)
+}
+
+// RelationOneContext is RelationOne with context, the context is passed to the relation queries
+func RelationOneContext(ctx context.Context, wrapper *ModelWrapper, db *DB, data interface{}) error {
 	refVal := reflect.Indirect(reflect.ValueOf(data))
 	t := refVal.Type()
 
@@ -70,7 +77,7 @@ func RelationOne(wrapper *ModelWrapper, db *DB, data interface{}) error {
 		if field.Type.Kind() == reflect.Slice {
 			foreignModel = reflect.New(field.Type)
 			// m := newModel(foreignModel, connection)
-			m := newModelWithWrapper(wrapper, db, foreignModel, connection)
+			m := newModelWithWrapper(ctx, wrapper, db, foreignModel, connection)
 
 			if chainFn, ok := db.RelationMap[name]; ok {
 				chainFn(m)
@@ -78,7 +85,7 @@ func RelationOne(wrapper *ModelWrapper, db *DB, data interface{}) error {
 
 			// batch get field values
 			// Since the structure is slice, there is no need to new Value
-			err := m.Where(fmt.Sprintf("%s=%s", relations[1], m.dialect.Placeholder()), mapper.FieldByName(refVal, relations[0]).Interface()).All()
+			err := m.Where(fmt.Sprintf("%s=?", relations[1]), mapper.FieldByName(refVal, relations[0]).Interface()).All()
 			if err != nil {
 				return err
 			}
@@ -95,12 +102,12 @@ func RelationOne(wrapper *ModelWrapper, db *DB, data interface{}) error {
 			// If field type is struct the one-to-one,eg: *Struct
 			foreignModel = reflect.New(field.Type.Elem())
 			// m := newModel(foreignModel, connection)
-			m := newModelWithWrapper(wrapper, db, foreignModel, connection)
+			m := newModelWithWrapper(ctx, wrapper, db, foreignModel, connection)
 			if chainFn, ok := db.RelationMap[name]; ok {
 				chainFn(m)
 			}
 
-			err := m.Where(fmt.Sprintf("%s=%s", relations[1], m.dialect.Placeholder()), mapper.FieldByName(refVal, relations[0]).Interface()).Get()
+			err := m.Where(fmt.Sprintf("%s=?", relations[1]), mapper.FieldByName(refVal, relations[0]).Interface()).Get()
 			// If one-to-one NoRows is not an error that needs to be terminated
 			if err != nil && err != sql.ErrNoRows {
 				return err
@@ -116,6 +123,11 @@ func RelationOne(wrapper *ModelWrapper, db *DB, data interface{}) error {
 
 // RelationAll is gets the associated relational data for multiple pieces of data
 func RelationAll(wrapper *ModelWrapper, db *DB, data interface{}) error {
+	return RelationAllContext(context.Background(), wrapper, db, data)
+}
+
+// RelationAllContext is RelationAll with context, the context is passed to the relation queries
+func RelationAllContext(ctx context.Context, wrapper *ModelWrapper, db *DB, data interface{}) error {
 	refVal := reflect.Indirect(reflect.ValueOf(data))
 
 	l := refVal.Len()
@@ -146,14 +158,14 @@ func RelationAll(wrapper *ModelWrapper, db *DB, data interface{}) error {
 		if field.Type.Kind() == reflect.Slice {
 			foreignModel = reflect.New(field.Type)
 			// m := newModel(foreignModel, connection)
-			m := newModelWithWrapper(wrapper, db, foreignModel, connection)
+			m := newModelWithWrapper(ctx, wrapper, db, foreignModel, connection)
 			if chainFn, ok := db.RelationMap[name]; ok {
 				chainFn(m)
 			}
 
 			// batch get field values
 			// Since the structure is slice, there is no need to new Value
-			err := m.Where(fmt.Sprintf("%s in(%s)", relations[1], m.dialect.Placeholder()), relVals).All()
+			err := m.Where(fmt.Sprintf("%s in(?)", relations[1]), relVals).All()
 			if err != nil {
 				return err
 			}
@@ -190,14 +202,13 @@ func RelationAll(wrapper *ModelWrapper, db *DB, data interface{}) error {
 			// Batch get field values, but must new slice []*Struct
 			fi := reflect.New(reflect.SliceOf(foreignModel.Type()))
 			// m := newModel(fi, connection)
-			m := newModelWithWrapper(wrapper, db, fi, connection)
+			m := newModelWithWrapper(ctx, wrapper, db, fi, connection)
 
 			if chainFn, ok := db.RelationMap[name]; ok {
 				chainFn(m)
 			}
 
-			// TODO sqlx.In maybe not support postgres
-			err := m.Where(fmt.Sprintf("%s in(%s)", relations[1], m.dialect.Placeholder()), relVals).All()
+			err := m.Where(fmt.Sprintf("%s in(?)", relations[1]), relVals).All()
 			if err != nil {
 				return err
 			}
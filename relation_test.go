@@ -1,6 +1,7 @@
 package gosql
 
 import (
+	"context"
 	"testing"
 
 	"github.com/ilibs/gosql/v2/internal/example/models"
@@ -145,3 +146,21 @@ func TestRelationModelWrapper(t *testing.T) {
 		}
 	})
 }
+
+func TestRelationOneContext(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		initDatas(t)
+		moment := &MomentList{}
+		err := Model(moment).Where("id = ?", 14).Get()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if err := RelationOneContext(ctx, nil, Use("default"), moment); err != context.Canceled {
+			t.Error("relation query must be canceled", err)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package gosql
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ReplicaPolicy chooses the replica that serves a read query
+type ReplicaPolicy interface {
+	Pick(replicas []*sqlx.DB) *sqlx.DB
+}
+
+type roundRobinPolicy struct {
+	next uint64
+}
+
+// RoundRobin returns a policy that cycles through the replicas in order
+func RoundRobin() ReplicaPolicy {
+	return &roundRobinPolicy{}
+}
+
+func (p *roundRobinPolicy) Pick(replicas []*sqlx.DB) *sqlx.DB {
+	n := atomic.AddUint64(&p.next, 1)
+	return replicas[(n-1)%uint64(len(replicas))]
+}
+
+type randomPolicy struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// Random returns a policy that picks a replica at random
+func Random() ReplicaPolicy {
+	return &randomPolicy{rand: rand.New(rand.NewSource(rand.Int63()))}
+}
+
+func (p *randomPolicy) Pick(replicas []*sqlx.DB) *sqlx.DB {
+	p.mu.Lock()
+	i := p.rand.Intn(len(replicas))
+	p.mu.Unlock()
+	return replicas[i]
+}
+
+type leastConnPolicy struct{}
+
+// LeastConn returns a policy that picks the replica with the fewest connections in use
+func LeastConn() ReplicaPolicy {
+	return leastConnPolicy{}
+}
+
+func (leastConnPolicy) Pick(replicas []*sqlx.DB) *sqlx.DB {
+	picked := replicas[0]
+	inUse := picked.Stats().InUse
+	for _, db := range replicas[1:] {
+		if n := db.Stats().InUse; n < inUse {
+			picked, inUse = db, n
+		}
+	}
+	return picked
+}
+
+var replicaPolicies = map[string]func() ReplicaPolicy{
+	"round_robin": RoundRobin,
+	"random":      Random,
+	"least_conn":  LeastConn,
+}
+
+// RegisterReplicaPolicy register a replica policy that can be used by Config.ReplicaPolicy
+func RegisterReplicaPolicy(name string, fn func() ReplicaPolicy) {
+	replicaPolicies[name] = fn
+}
+
+func newReplicaPolicy(name string) (ReplicaPolicy, error) {
+	if name == "" {
+		return RoundRobin(), nil
+	}
+
+	if fn, ok := replicaPolicies[name]; ok {
+		return fn(), nil
+	}
+	return nil, fmt.Errorf("replica policy `%s` is not registered", name)
+}
+
+// replicaSet is the read replicas of a database link
+type replicaSet struct {
+	dbs    []*sqlx.DB
+	policy ReplicaPolicy
+}
+
+func (r *replicaSet) pick() *sqlx.DB {
+	if len(r.dbs) == 1 {
+		return r.dbs[0]
+	}
+	return r.policy.Pick(r.dbs)
+}
+
+func (r *replicaSet) close() {
+	for _, db := range r.dbs {
+		_ = db.Close()
+	}
+}
@@ -0,0 +1,121 @@
+package gosql
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ilibs/gosql/v2/internal/example/models"
+)
+
+func testReplicas(t *testing.T, n int) []*sqlx.DB {
+	replicas := make([]*sqlx.DB, n)
+	for i := range replicas {
+		db, err := sqlx.Open("mysql", "root:123456@tcp(127.0.0.1:3306)/test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		replicas[i] = db
+	}
+	return replicas
+}
+
+func TestRoundRobin(t *testing.T) {
+	replicas := testReplicas(t, 3)
+	p := RoundRobin()
+
+	for i := 0; i < 6; i++ {
+		if got := p.Pick(replicas); got != replicas[i%3] {
+			t.Errorf("round robin pick %d error", i)
+		}
+	}
+}
+
+func TestRandom(t *testing.T) {
+	replicas := testReplicas(t, 3)
+	p := Random()
+
+	for i := 0; i < 10; i++ {
+		got := p.Pick(replicas)
+		if got != replicas[0] && got != replicas[1] && got != replicas[2] {
+			t.Error("random pick error")
+		}
+	}
+}
+
+func TestLeastConn(t *testing.T) {
+	replicas := testReplicas(t, 2)
+	if got := LeastConn().Pick(replicas); got != replicas[0] {
+		t.Error("least conn pick error")
+	}
+}
+
+func Test_newReplicaPolicy(t *testing.T) {
+	for _, name := range []string{"", "round_robin", "random", "least_conn"} {
+		if _, err := newReplicaPolicy(name); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if _, err := newReplicaPolicy("unknown"); err == nil {
+		t.Error("unknown replica policy must error")
+	}
+}
+
+func TestDB_UsePrimary(t *testing.T) {
+	dsn := os.Getenv("MYSQL_TEST_DSN1")
+
+	if dsn == "" {
+		dsn = "root:123456@tcp(127.0.0.1:3306)/test?charset=utf8&parseTime=True&loc=Asia%2FShanghai"
+	}
+
+	RunWithSchema(t, func(t *testing.T) {
+		insert(1)
+
+		db, err := Open("mysql", dsn, WithReplicas(dsn, dsn), WithReplicaPolicy(LeastConn()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if db.reader().(*sqlx.DB).DB == db.database.DB {
+			t.Error("read queries must use the replicas")
+		}
+
+		user := &models.Users{}
+		if err := db.Model(user).Where("id = ?", 1).Get(); err != nil {
+			t.Error(err)
+		}
+
+		if err := db.Model(user).UsePrimary().Where("id = ?", 1).Get(); err != nil {
+			t.Error(err)
+		}
+
+		if db.reader().(*sqlx.DB).DB == db.database.DB {
+			t.Error("Builder.UsePrimary must not change the DB")
+		}
+
+		if db.UsePrimary().reader().(*sqlx.DB).DB != db.database.DB {
+			t.Error("UsePrimary must read from the primary")
+		}
+
+		if db.primary || db.reader().(*sqlx.DB).DB == db.database.DB {
+			t.Error("DB.UsePrimary must not change the DB")
+		}
+	})
+}
+
+func TestBuilder_UsePrimary(t *testing.T) {
+	db := &DB{}
+	b := (&Builder{db: db}).UsePrimary()
+	m := (&Mapper{db: db}).UsePrimary()
+
+	if db.primary || !b.db.primary || !m.db.primary {
+		t.Error("UsePrimary must only change the builder", db.primary, b.db.primary, m.db.primary)
+	}
+
+	tx := &DB{tx: &sqlx.Tx{}}
+	if (&Builder{db: tx}).UsePrimary().db != tx {
+		t.Error("the transaction must not be copied")
+	}
+}
@@ -0,0 +1,147 @@
+package gosql
+
+import (
+	"context"
+	"reflect"
+)
+
+// Repository is the typed query of the model T, T is the pointer of the model struct, for example:
+// users, err := gosql.Query[*Users](gosql.Use("default")).Where("status = ?", 1).Find()
+type Repository[T IModel] struct {
+	db     *DB
+	ctx    context.Context
+	chains []BuilderChainFunc
+}
+
+// Query returns the typed query of the model T built on Builder
+func Query[T IModel](db *DB) *Repository[T] {
+	return &Repository[T]{db: db}
+}
+
+// WithContext returns a copy of the repository whose statements are executed with the context
+func (r *Repository[T]) WithContext(ctx context.Context) *Repository[T] {
+	c := r.clone()
+	c.ctx = ctx
+	return c
+}
+
+// Scope returns a copy of the repository that applies fn to the builder of each statement,
+// the repository itself is not changed so it can be reused
+func (r *Repository[T]) Scope(fn BuilderChainFunc) *Repository[T] {
+	c := r.clone()
+	c.chains = append(c.chains, fn)
+	return c
+}
+
+// clone copies the repository with its own chains
+func (r *Repository[T]) clone() *Repository[T] {
+	c := *r
+	c.chains = append(make([]BuilderChainFunc, 0, len(r.chains)+1), r.chains...)
+	return &c
+}
+
+// Where see Builder.Where
+func (r *Repository[T]) Where(query interface{}, args ...interface{}) *Repository[T] {
+	return r.Scope(func(b *Builder) {
+		b.Where(query, args...)
+	})
+}
+
+// OrWhere see Builder.OrWhere
+func (r *Repository[T]) OrWhere(query interface{}, args ...interface{}) *Repository[T] {
+	return r.Scope(func(b *Builder) {
+		b.OrWhere(query, args...)
+	})
+}
+
+// Select see Builder.Select
+func (r *Repository[T]) Select(fields string) *Repository[T] {
+	return r.Scope(func(b *Builder) {
+		b.Select(fields)
+	})
+}
+
+// OrderBy see Builder.OrderBy
+func (r *Repository[T]) OrderBy(str string) *Repository[T] {
+	return r.Scope(func(b *Builder) {
+		b.OrderBy(str)
+	})
+}
+
+// Limit see Builder.Limit
+func (r *Repository[T]) Limit(i int) *Repository[T] {
+	return r.Scope(func(b *Builder) {
+		b.Limit(i)
+	})
+}
+
+// Offset see Builder.Offset
+func (r *Repository[T]) Offset(i int) *Repository[T] {
+	return r.Scope(func(b *Builder) {
+		b.Offset(i)
+	})
+}
+
+// builder returns the builder of the model with the context and the chained conditions
+func (r *Repository[T]) builder(model interface{}) *Builder {
+	b := r.db.Model(model)
+	if r.ctx != nil {
+		b.ctx = r.ctx
+	}
+	for _, fn := range r.chains {
+		fn(b)
+	}
+	return b
+}
+
+// First returns the first row, sql.ErrNoRows if there is none
+func (r *Repository[T]) First() (T, error) {
+	model := newModelOf[T]()
+	if err := r.builder(model).Limit(1).Get(); err != nil {
+		var zero T
+		return zero, err
+	}
+	return model, nil
+}
+
+// Find returns all the rows
+func (r *Repository[T]) Find() ([]T, error) {
+	rows := make([]T, 0)
+	if err := r.builder(&rows).All(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Count returns the number of the rows
+func (r *Repository[T]) Count() (int64, error) {
+	return r.builder(newModelOf[T]()).Count()
+}
+
+// Create inserts the model and returns it with the primary key filled
+func (r *Repository[T]) Create(model T) (T, error) {
+	if _, err := r.builder(model).Create(); err != nil {
+		return model, err
+	}
+	return model, nil
+}
+
+// Update updates the row of the model, see Builder.Update
+func (r *Repository[T]) Update(model T, zeroValues ...string) (int64, error) {
+	return r.builder(model).Update(zeroValues...)
+}
+
+// Delete deletes the row of the model, see Builder.Delete
+func (r *Repository[T]) Delete(model T, zeroValues ...string) (int64, error) {
+	return r.builder(model).Delete(zeroValues...)
+}
+
+// newModelOf allocates the struct that the pointer type T points to
+func newModelOf[T IModel]() T {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Ptr {
+		var zero T
+		return zero
+	}
+	return reflect.New(t.Elem()).Interface().(T)
+}
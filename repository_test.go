@@ -0,0 +1,85 @@
+package gosql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/ilibs/gosql/v2/internal/example/models"
+)
+
+func Test_newModelOf(t *testing.T) {
+	user := newModelOf[*models.Users]()
+	if user == nil {
+		t.Fatal("new model error")
+	}
+
+	user.Name = "test"
+	if other := newModelOf[*models.Users](); other == user || other.Name != "" {
+		t.Error("new model must be allocated every time")
+	}
+}
+
+func TestRepository_Scope(t *testing.T) {
+	users := Query[*models.Users](nil)
+	active := users.Where("status = ?", 1)
+	page := active.Limit(10)
+	active.OrderBy("id desc")
+
+	if len(users.chains) != 0 || len(active.chains) != 1 || len(page.chains) != 2 {
+		t.Error("the chained conditions must not change the repository", len(users.chains), len(active.chains), len(page.chains))
+	}
+
+	if ctx := context.Background(); users.WithContext(ctx).ctx != ctx || users.ctx != nil {
+		t.Error("with context must not change the repository")
+	}
+}
+
+func TestRepository(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		users := Query[*models.Users](Use(defaultLink)).WithContext(context.Background())
+
+		for _, name := range []string{"a", "b", "c"} {
+			user, err := users.Create(&models.Users{Name: name, Status: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if user.Id == 0 {
+				t.Error("primary key must be filled")
+			}
+		}
+
+		user, err := Query[*models.Users](Use(defaultLink)).Where("name = ?", "b").First()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.Name != "b" {
+			t.Error("first error", user.Name)
+		}
+
+		user.Status = 2
+		if affected, err := users.Update(user); err != nil || affected != 1 {
+			t.Error("update error", affected, err)
+		}
+
+		rows, err := users.Where("status = ?", 1).OrderBy("id desc").Find()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != 2 || rows[0].Name != "c" {
+			t.Error("find error", rows)
+		}
+
+		if affected, err := users.Delete(rows[0]); err != nil || affected != 1 {
+			t.Error("delete error", affected, err)
+		}
+
+		if num, err := Query[*models.Users](Use(defaultLink)).Count(); err != nil || num != 2 {
+			t.Error("count error", num, err)
+		}
+
+		if _, err := Query[*models.Users](Use(defaultLink)).Where("name = ?", "x").First(); err != sql.ErrNoRows {
+			t.Error("first must return sql.ErrNoRows", err)
+		}
+	})
+}
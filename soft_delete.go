@@ -0,0 +1,143 @@
+package gosql
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	// trashedExclude the soft deleted rows are filtered out, it is the default
+	trashedExclude = iota
+	// trashedWith the soft deleted rows are included
+	trashedWith
+	// trashedOnly only the soft deleted rows are selected
+	trashedOnly
+)
+
+// errNoSoftDelete is returned by Restore if the model has no soft delete column
+var errNoSoftDelete = errors.New("model has no soft delete column, see gosql.SOFT_DELETE_FIELDS")
+
+// Unscoped the soft deleted rows are included and Delete removes the rows permanently
+func (b *Builder) Unscoped() *Builder {
+	b.unscoped = true
+	return b
+}
+
+// WithTrashed the soft deleted rows are included
+func (b *Builder) WithTrashed() *Builder {
+	b.trashed = trashedWith
+	return b
+}
+
+// OnlyTrashed only the soft deleted rows are selected
+func (b *Builder) OnlyTrashed() *Builder {
+	b.trashed = trashedOnly
+	return b
+}
+
+// ForceDelete removes the rows permanently even if the model has a soft delete column
+func (b *Builder) ForceDelete(zeroValues ...string) (affected int64, err error) {
+	return b.Unscoped().Delete(zeroValues...)
+}
+
+// Restore clears the soft delete column of the soft deleted rows
+// gosql.Model(&User{Id:1}).Restore()
+func (b *Builder) Restore(zeroValues ...string) (affected int64, err error) {
+	b.initModel()
+	column, field := b.softDeleteField()
+	if column == "" {
+		return 0, errNoSoftDelete
+	}
+
+	m := zeroValueFilter(b.reflectModel(nil), zeroValues)
+	delete(m, column)
+	// If where is empty, the primary key where condition is generated automatically
+	b.generateWhere(m)
+	b.OnlyTrashed().softDeleteScope()
+
+	result, err := b.db.ExecContext(b.context(), b.updateString(map[string]interface{}{column: restoredValue(field.Type())}), b.args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// softDeleteField returns the first column of SOFT_DELETE_FIELDS that the model has
+func (b *Builder) softDeleteField() (string, reflect.Value) {
+	fields := mapper.FieldMap(b.modelReflectValue)
+	for _, name := range SOFT_DELETE_FIELDS {
+		if v, ok := fields[name]; ok {
+			return name, v
+		}
+	}
+	return "", reflect.Value{}
+}
+
+// softDeleteScope filters the rows by the soft delete column, the scope is applied only once
+func (b *Builder) softDeleteScope() {
+	if b.scoped || b.unscoped || b.trashed == trashedWith {
+		return
+	}
+	b.scoped = true
+
+	name, field := b.softDeleteField()
+	if name == "" {
+		return
+	}
+
+	column := b.qualifiedColumn(name)
+
+	trashed := b.trashed == trashedOnly
+	switch kind := field.Kind(); {
+	case kind == reflect.Bool:
+		b.scope(column + " = " + b.dialect.BoolLiteral(trashed))
+	case isNumberKind(kind) && trashed:
+		b.scope(column + " <> 0")
+	case isNumberKind(kind):
+		b.scope(column + " = 0")
+	case trashed:
+		b.scope(column + " IS NOT NULL")
+	default:
+		b.scope(column + " IS NULL")
+	}
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// deletedValue the value that marks the row deleted, the deleted time for time columns,
+// true or 1 for flag columns such as is_deleted
+func deletedValue(name string, t reflect.Type) interface{} {
+	switch kind := t.Kind(); {
+	case kind == reflect.Bool:
+		return true
+	case isNumberKind(kind) && strings.HasPrefix(name, "is_"):
+		return 1
+	case isNumberKind(kind):
+		return time.Now().Unix()
+	case kind == reflect.String:
+		return time.Now().Format("2006-01-02 15:04:05")
+	}
+	return time.Now().Truncate(time.Second)
+}
+
+// restoredValue the value that marks the row not deleted
+func restoredValue(t reflect.Type) interface{} {
+	switch kind := t.Kind(); {
+	case kind == reflect.Bool:
+		return false
+	case isNumberKind(kind):
+		return 0
+	}
+	return nil
+}
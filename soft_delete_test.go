@@ -0,0 +1,168 @@
+package gosql
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ilibs/gosql/v2/internal/example/models"
+)
+
+type softPost struct {
+	Id        int          `db:"id"`
+	Title     string       `db:"title"`
+	DeletedAt sql.NullTime `db:"deleted_at"`
+	CreatedAt time.Time    `db:"created_at"`
+	UpdatedAt time.Time    `db:"updated_at"`
+}
+
+func (p *softPost) TableName() string {
+	return "posts"
+}
+
+func (p *softPost) PK() string {
+	return "id"
+}
+
+type flagPost struct {
+	Id        int    `db:"id"`
+	IsDeleted bool   `db:"is_deleted"`
+	Title     string `db:"title"`
+}
+
+func (p *flagPost) TableName() string {
+	return "posts"
+}
+
+func (p *flagPost) PK() string {
+	return "id"
+}
+
+func TestBuilder_softDeleteScope(t *testing.T) {
+	newBuilder := func(model IModel) *Builder {
+		return &Builder{
+			modelReflectValue: reflect.ValueOf(model),
+			SQLBuilder: SQLBuilder{
+				dialect: mustGetDialect("mysql"),
+				table:   model.TableName(),
+			},
+		}
+	}
+
+	b := newBuilder(&softPost{})
+	b.Where("id = ?", 1).OrWhere("title = ?", "test")
+	b.softDeleteScope()
+	b.softDeleteScope()
+	if query := b.queryString(); query != "SELECT * FROM `posts` WHERE ((id = ?) OR (title = ?)) AND (`deleted_at` IS NULL);" {
+		t.Error("soft delete scope error", query)
+	}
+
+	b = newBuilder(&softPost{})
+	b.OnlyTrashed().softDeleteScope()
+	if query := b.queryString(); query != "SELECT * FROM `posts` WHERE (`deleted_at` IS NOT NULL);" {
+		t.Error("only trashed scope error", query)
+	}
+
+	b = newBuilder(&softPost{})
+	b.Join("users u", "u.id = posts.user_id").softDeleteScope()
+	if query := b.countString(); query != "SELECT count(*) FROM `posts` JOIN `users` AS `u` ON u.id = posts.user_id WHERE (`posts`.`deleted_at` IS NULL);" {
+		t.Error("join soft delete scope error", query)
+	}
+
+	for _, b := range []*Builder{newBuilder(&softPost{}).WithTrashed(), newBuilder(&softPost{}).Unscoped()} {
+		b.softDeleteScope()
+		if query := b.queryString(); query != "SELECT * FROM `posts`;" {
+			t.Error("with trashed scope error", query)
+		}
+	}
+
+	b = newBuilder(&flagPost{})
+	b.softDeleteScope()
+	if query := b.queryString(); query != "SELECT * FROM `posts` WHERE (`is_deleted` = FALSE);" {
+		t.Error("bool soft delete scope error", query)
+	}
+
+	b = newBuilder(&models.Users{})
+	b.softDeleteScope()
+	if query := b.queryString(); query != "SELECT * FROM `users`;" {
+		t.Error("model without soft delete column error", query)
+	}
+}
+
+func Test_deletedValue(t *testing.T) {
+	if v := deletedValue("is_deleted", reflect.TypeOf(0)); v != 1 {
+		t.Error("flag deleted value error", v)
+	}
+
+	if v, ok := deletedValue("deleted_at", reflect.TypeOf(int64(0))).(int64); !ok || v < time.Now().Add(-time.Minute).Unix() {
+		t.Error("unix deleted value error", v)
+	}
+
+	if v := deletedValue("is_deleted", reflect.TypeOf(false)); v != true {
+		t.Error("bool deleted value error", v)
+	}
+
+	if _, ok := deletedValue("deleted_at", reflect.TypeOf(sql.NullTime{})).(time.Time); !ok {
+		t.Error("time deleted value error")
+	}
+
+	if v := restoredValue(reflect.TypeOf(sql.NullTime{})); v != nil {
+		t.Error("restored value error", v)
+	}
+}
+
+func TestBuilder_SoftDelete(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		for i := 1; i <= 3; i++ {
+			if _, err := Model(&softPost{Title: "test"}).Create(); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if _, err := Model(&softPost{Id: 1}).Delete(); err != nil {
+			t.Fatal(err)
+		}
+
+		count := func(b *Builder) int64 {
+			num, err := b.Count()
+			if err != nil {
+				t.Fatal(err)
+			}
+			return num
+		}
+
+		if num := count(Model(&softPost{})); num != 2 {
+			t.Error("soft deleted rows must be filtered", num)
+		}
+
+		if num := count(Model(&softPost{}).WithTrashed()); num != 3 {
+			t.Error("with trashed count error", num)
+		}
+
+		if num := count(Model(&softPost{}).OnlyTrashed()); num != 1 {
+			t.Error("only trashed count error", num)
+		}
+
+		if err := Model(&softPost{Id: 1}).Get(); err != sql.ErrNoRows {
+			t.Error("soft deleted row must not be found", err)
+		}
+
+		if _, err := Model(&softPost{Id: 1}).Restore(); err != nil {
+			t.Fatal(err)
+		}
+
+		post := &softPost{Id: 1}
+		if err := Model(post).Get(); err != nil {
+			t.Error("restored row must be found", err)
+		}
+
+		if _, err := Model(&softPost{Id: 2}).ForceDelete(); err != nil {
+			t.Fatal(err)
+		}
+
+		if num := count(Model(&softPost{}).Unscoped()); num != 2 {
+			t.Error("force delete error", num)
+		}
+	})
+}
@@ -1,116 +1,558 @@
 package gosql
 
 import (
+	"database/sql/driver"
 	"fmt"
+	"log"
+	"reflect"
 	"strings"
 )
 
 type SQLBuilder struct {
-	dialect    Dialect
+	dialect    sqlDialect
 	fields     string
 	table      string
 	forceIndex string
+	joins      []string
 	where      string
+	groupBy    string
+	having     string
+	distinct   bool
 	order      string
 	limit      string
 	offset     string
 	hint       string
+	// optimizerHint is rendered by the dialect, hint is placed before the statement as is
+	optimizerHint string
+	// lock is the row lock mode UPDATE or SHARE, lockOption is NOWAIT or SKIP LOCKED
+	lock       string
+	lockOption string
+	returning  []string
 	// Extra args to be substituted in the *where* clause
 	args []interface{}
+	// Extra args to be substituted in the *join* clause
+	joinArgs []interface{}
+	// Extra args to be substituted in the *having* clause
+	havingArgs []interface{}
 }
 
+// limitFormat the pagination clause is rendered by the dialect
 func (s *SQLBuilder) limitFormat() string {
-	if s.limit != "" {
-		return fmt.Sprintf("LIMIT %s", s.limit)
+	return s.dialect.LimitOffset(s.limit, s.offset, s.order != "")
+}
+
+func (s *SQLBuilder) orderFormat() string {
+	if s.order != "" {
+		return fmt.Sprintf("ORDER BY %s", s.order)
 	}
 	return ""
 }
 
-func (s *SQLBuilder) offsetFormat() string {
-	if s.offset != "" {
-		return fmt.Sprintf("OFFSET %s", s.offset)
+func (s *SQLBuilder) joinFormat() string {
+	return strings.Join(s.joins, " ")
+}
+
+// quoteColumn quotes a column name, the table prefix is quoted separately, for example users.id
+// a column that is already quoted or is an expression is not changed
+func quoteColumn(d Dialect, name string) string {
+	if strings.ContainsAny(name, "`\"[]()* ") {
+		return name
+	}
+
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = d.Quote(p)
+	}
+	return strings.Join(parts, ".")
+}
+
+func (s *SQLBuilder) quoteColumn(name string) string {
+	return quoteColumn(s.dialect, name)
+}
+
+// quoteTable quotes a table name with optional alias, for example "users u" or "users AS u"
+func (s *SQLBuilder) quoteTable(table string) string {
+	parts := strings.Fields(table)
+	switch {
+	case len(parts) == 1:
+		return s.quoteColumn(parts[0])
+	case len(parts) == 2:
+		return s.quoteColumn(parts[0]) + " AS " + s.dialect.Quote(parts[1])
+	case len(parts) == 3 && strings.EqualFold(parts[1], "as"):
+		return s.quoteColumn(parts[0]) + " AS " + s.dialect.Quote(parts[2])
+	}
+	return table
+}
+
+// tableRef is the name that columns of the table are referenced by, the alias if there is one
+func (s *SQLBuilder) tableRef() string {
+	parts := strings.Fields(s.table)
+	if len(parts) > 1 {
+		return s.dialect.Quote(parts[len(parts)-1])
+	}
+	return s.quoteColumn(s.table)
+}
+
+// qualifiedColumn quotes the column and prefixes it with the table when there are joins,
+// because the columns of joined tables may have the same name
+func (s *SQLBuilder) qualifiedColumn(name string) string {
+	column := s.quoteColumn(name)
+	if len(s.joins) > 0 {
+		column = s.tableRef() + "." + column
+	}
+	return column
+}
+
+// fromFormat Assemble the table and join clause
+func (s *SQLBuilder) fromFormat() string {
+	table := s.quoteTable(s.table)
+	if s.forceIndex != "" {
+		if hint := s.dialect.IndexHint(s.forceIndex); hint != "" {
+			table += " " + hint
+		}
+	}
+
+	if len(s.joins) > 0 {
+		table += " " + s.joinFormat()
+	}
+	return table
+}
+
+// queryArgs returns the args of the query and count statement in clause order
+func (s *SQLBuilder) queryArgs() []interface{} {
+	if len(s.joinArgs) == 0 && len(s.havingArgs) == 0 {
+		return s.args
+	}
+
+	args := make([]interface{}, 0, len(s.joinArgs)+len(s.args)+len(s.havingArgs))
+	args = append(args, s.joinArgs...)
+	args = append(args, s.args...)
+	return append(args, s.havingArgs...)
+}
+
+func (s *SQLBuilder) lockFormat() string {
+	if s.lock != "" {
+		return s.dialect.Lock(s.lock, s.lockOption)
 	}
 	return ""
 }
 
-func (s *SQLBuilder) orderFormat() string {
-	if s.order != "" {
-		return fmt.Sprintf("ORDER BY %s", s.order)
+func (s *SQLBuilder) groupFormat() string {
+	if s.groupBy != "" {
+		return fmt.Sprintf("GROUP BY %s", s.groupBy)
 	}
 	return ""
 }
 
-// queryString Assemble the query statement
-func (s *SQLBuilder) queryString() string {
-	if s.fields == "" {
-		s.fields = "*"
+func (s *SQLBuilder) selectFields() string {
+	fields := s.fields
+	if fields == "" {
+		fields = "*"
+		// the columns of joined tables may overwrite the model columns with the same name
+		if len(s.joins) > 0 {
+			fields = s.tableRef() + ".*"
+		}
 	}
 
-	table := s.dialect.Quote(s.table)
-	if s.forceIndex != "" {
-		table += fmt.Sprintf(" force index(%s)", s.forceIndex)
+	if s.distinct {
+		return "DISTINCT " + fields
+	}
+	return fields
+}
+
+// joinClauses joins the non-empty clauses with a space
+func joinClauses(clauses ...string) string {
+	parts := make([]string, 0, len(clauses))
+	for _, c := range clauses {
+		if c != "" {
+			parts = append(parts, c)
+		}
 	}
+	return strings.Join(parts, " ")
+}
 
-	query := fmt.Sprintf("%sSELECT %s FROM %s %s %s %s %s", s.hint, s.fields, table, s.where, s.orderFormat(), s.limitFormat(), s.offsetFormat())
-	query = strings.TrimRight(query, " ")
-	query = query + ";"
+// selectString Assemble the select statement without order and limit
+func (s *SQLBuilder) selectString(fields string) string {
+	return joinClauses("SELECT "+fields, "FROM "+s.fromFormat(), s.where, s.groupFormat(), s.having)
+}
 
-	return query
+// queryString Assemble the query statement
+func (s *SQLBuilder) queryString() string {
+	query := joinClauses(s.selectString(s.selectFields()), s.orderFormat(), s.limitFormat(), s.lockFormat())
+
+	return s.rebind(s.selectStatement(query))
 }
 
 // countString Assemble the count statement
+// the grouped or distinct rows are counted by a subquery
 func (s *SQLBuilder) countString() string {
-	query := fmt.Sprintf("%sSELECT count(*) FROM %s %s", s.hint, s.dialect.Quote(s.table), s.where)
-	query = strings.TrimRight(query, " ")
-	query = query + ";"
+	var query string
+	switch {
+	case s.distinct:
+		query = fmt.Sprintf("SELECT count(*) FROM (%s) AS t", s.selectString(s.selectFields()))
+	case s.groupBy != "":
+		query = fmt.Sprintf("SELECT count(*) FROM (%s) AS t", s.selectString("1"))
+	default:
+		query = s.selectString("count(*)")
+	}
 
-	return query
+	return s.rebind(s.selectStatement(query))
+}
+
+// aggregateString Assemble the aggregate statement, such as SUM(column),
+// the grouped rows are aggregated by a subquery of the aggregates of the groups,
+// AVG of the grouped rows is the sum of the group sums divided by the sum of the group counts,
+// the distinct values of the column are aggregated if Distinct is set
+func (s *SQLBuilder) aggregateString(fn, column string) string {
+	column = s.quoteColumn(column)
+	if s.distinct {
+		column = "DISTINCT " + column
+	}
+	aggregate := fmt.Sprintf("%s(%s)", fn, column)
+
+	var query string
+	switch {
+	case s.groupBy != "" && fn == "AVG":
+		subquery := s.selectString(fmt.Sprintf("SUM(%s) AS total, COUNT(%s) AS num", column, column))
+		query = fmt.Sprintf("SELECT SUM(t.total) * 1.0 / NULLIF(SUM(t.num), 0) FROM (%s) AS t", subquery)
+	case s.groupBy != "":
+		query = fmt.Sprintf("SELECT %s(t.aggregate) FROM (%s) AS t", fn, s.selectString(aggregate+" AS aggregate"))
+	default:
+		query = s.selectString(aggregate)
+	}
+
+	return s.rebind(s.selectStatement(query))
+}
+
+// pluckString Assemble the statement that selects the column
+func (s *SQLBuilder) pluckString(column string) string {
+	column = s.quoteColumn(column)
+	if s.distinct {
+		column = "DISTINCT " + column
+	}
+	query := joinClauses(s.selectString(column), s.orderFormat(), s.limitFormat())
+
+	return s.rebind(s.selectStatement(query))
+}
+
+// existsString Assemble the statement that selects at most one row
+func (s *SQLBuilder) existsString() string {
+	query := joinClauses(s.selectString("1"), s.dialect.LimitOffset("1", "", false))
+
+	return s.rebind(s.selectStatement(query))
+}
+
+// selectStatement adds the hints and terminator to the select statement
+func (s *SQLBuilder) selectStatement(query string) string {
+	if s.optimizerHint != "" {
+		head, inline, tail := s.dialect.OptimizerHint(s.optimizerHint)
+		if inline != "" {
+			query = "SELECT " + inline + " " + strings.TrimPrefix(query, "SELECT ")
+		}
+		query = head + joinClauses(query, tail)
+	}
+
+	return s.hint + query + s.dialect.Terminator()
+}
+
+// returningFields the quoted returning columns
+func (s *SQLBuilder) returningFields() string {
+	cols := make([]string, len(s.returning))
+	for i, col := range s.returning {
+		cols[i] = s.dialect.Quote(col)
+	}
+	return strings.Join(cols, ",")
 }
 
 // insertString Assemble the insert statement
 func (s *SQLBuilder) insertString(params map[string]interface{}) string {
-	var cols, vals []string
-	for _, k := range sortedParamKeys(params) {
-		cols = append(cols, s.dialect.Quote(k))
-		vals = append(vals, s.dialect.Placeholder())
-		s.args = append(s.args, params[k])
+	return s.insertRowsString(sortedParamKeys(params), []map[string]interface{}{params})
+}
+
+// insertRowsString Assemble the multi-row insert statement, every row must have the columns
+func (s *SQLBuilder) insertRowsString(columns []string, rows []map[string]interface{}) string {
+	return s.rebind(s.insertRows(columns, rows, "") + s.dialect.Terminator())
+}
+
+// insertReturningString Assemble the insert statement that returns the columns of the inserted row
+func (s *SQLBuilder) insertReturningString(params map[string]interface{}, columns []string) string {
+	return s.insertRowsReturningString(sortedParamKeys(params), []map[string]interface{}{params}, columns)
+}
+
+// insertRowsReturningString Assemble the multi-row insert statement that returns the columns of the inserted rows
+func (s *SQLBuilder) insertRowsReturningString(columns []string, rows []map[string]interface{}, returning []string) string {
+	if o, ok := s.dialect.(outputDialect); ok {
+		return s.rebind(s.insertRows(columns, rows, o.Output(returning)) + s.dialect.Terminator())
 	}
+	return s.rebind(s.insertRows(columns, rows, "") + " " + s.dialect.Returning(returning) + s.dialect.Terminator())
+}
 
-	return fmt.Sprintf("INSERT INTO %s (%s) VALUES(%s);", s.dialect.Quote(s.table), strings.Join(cols, ","), strings.Join(vals, ","))
+// insertRows the insert statement without terminator, the bind vars are not rebound,
+// output is the clause placed before VALUES, such as OUTPUT INSERTED.id
+func (s *SQLBuilder) insertRows(columns []string, rows []map[string]interface{}, output string) string {
+	cols := make([]string, len(columns))
+	for i, k := range columns {
+		cols[i] = s.dialect.Quote(k)
+	}
+
+	values := make([]string, len(rows))
+	for i, row := range rows {
+		for _, k := range columns {
+			s.args = append(s.args, row[k])
+		}
+		values[i] = "(" + bindVars(len(columns)) + ")"
+	}
+
+	if output != "" {
+		output = " " + output
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s)%s VALUES%s", s.dialect.Quote(s.table), strings.Join(cols, ","), output, strings.Join(values, ","))
+}
+
+// upsertError returns the error if the upsert can not be generated, ON CONFLICT needs the conflict columns,
+// MySQL detects the conflict by any unique key so they can be empty
+func (s *SQLBuilder) upsertError(conflictColumns []string) error {
+	if !s.dialect.Supports(FeatureUpsert) {
+		return unsupported(s.dialect, "upsert")
+	}
+
+	if len(conflictColumns) == 0 && s.dialect.GetName() != "mysql" {
+		return fmt.Errorf("upsert on %s needs the conflict columns", s.dialect.GetName())
+	}
+	return nil
+}
+
+// upsertString Assemble the insert statement that updates the conflicting row,
+// the update values can be gosql.Expr, for example Expr("like_total + ?", 1)
+func (s *SQLBuilder) upsertString(params map[string]interface{}, conflictColumns []string, update map[string]interface{}) string {
+	query := s.insertRows(sortedParamKeys(params), []map[string]interface{}{params}, "")
+
+	sets, args := s.assignments(update)
+	s.args = append(s.args, args...)
+
+	return s.rebind(query + " " + s.dialect.OnConflict(conflictColumns, sets) + s.dialect.Terminator())
+}
+
+// excludedUpdates updates the columns to the values that the insert proposed
+func (s *SQLBuilder) excludedUpdates(columns []string) map[string]interface{} {
+	update := make(map[string]interface{}, len(columns))
+	for _, k := range columns {
+		update[k] = Expr(s.dialect.Excluded(k))
+	}
+	return update
 }
 
 // updateString Assemble the update statement
 func (s *SQLBuilder) updateString(params map[string]interface{}) string {
-	var updateFields []string
+	sets, args := s.assignments(params)
+	args = append(args, s.args...)
+	s.args = args
+
+	var query string
+	if m, ok := s.dialect.(mutationDialect); ok {
+		query = m.UpdateStatement(s.dialect.Quote(s.table), sets, s.where)
+	} else {
+		query = fmt.Sprintf("UPDATE %s SET %s %s", s.dialect.Quote(s.table), sets, s.where)
+	}
+	query = strings.TrimRight(query, " ")
+	query = query + s.dialect.Terminator()
+	return s.rebind(query)
+}
+
+// updateReturningString Assemble the update statement that returns the columns of the updated rows
+func (s *SQLBuilder) updateReturningString(params map[string]interface{}, columns []string) string {
+	query := strings.TrimSuffix(s.updateString(params), s.dialect.Terminator())
+	return query + " " + s.dialect.Returning(columns) + s.dialect.Terminator()
+}
+
+// assignments the column=value list of the update statement, the values can be gosql.Expr
+func (s *SQLBuilder) assignments(params map[string]interface{}) (string, []interface{}) {
+	var sets []string
 	args := make([]interface{}, 0)
 
 	for _, k := range sortedParamKeys(params) {
 		if e, ok := params[k].(*expr); ok {
-			updateFields = append(updateFields, fmt.Sprintf("%s=%s", s.dialect.Quote(k), e.expr))
-			args = append(args, e.args...)
+			str, exprArgs := expandIn(e.expr, e.args)
+			sets = append(sets, fmt.Sprintf("%s=%s", s.dialect.Quote(k), str))
+			args = append(args, exprArgs...)
 		} else {
-			updateFields = append(updateFields, fmt.Sprintf("%s=%s", s.dialect.Quote(k), s.dialect.Placeholder()))
+			sets = append(sets, fmt.Sprintf("%s=?", s.dialect.Quote(k)))
 			args = append(args, params[k])
 		}
 	}
-	args = append(args, s.args...)
-	s.args = args
-
-	query := fmt.Sprintf("UPDATE %s SET %s %s", s.dialect.Quote(s.table), strings.Join(updateFields, ","), s.where)
-	query = strings.TrimRight(query, " ")
-	query = query + ";"
-	return query
+	return strings.Join(sets, ","), args
 }
 
 // deleteString Assemble the delete statement
 func (s *SQLBuilder) deleteString() string {
-	query := fmt.Sprintf("DELETE FROM %s %s", s.dialect.Quote(s.table), s.where)
+	var query string
+	if m, ok := s.dialect.(mutationDialect); ok {
+		query = m.DeleteStatement(s.dialect.Quote(s.table), s.where)
+	} else {
+		query = fmt.Sprintf("DELETE FROM %s %s", s.dialect.Quote(s.table), s.where)
+	}
 	query = strings.TrimRight(query, " ")
-	query = query + ";"
-	return query
+	query = query + s.dialect.Terminator()
+	return s.rebind(query)
+}
+
+// rebind replaces the ? bind vars of the statement by the dialect bind vars, such as $1 of postgres
+func (s *SQLBuilder) rebind(query string) string {
+	return rebind(s.dialect, query)
 }
 
-func (s *SQLBuilder) Where(str string, args ...interface{}) {
+// rebind numbers the ? bind vars in statement order, the ? in quoted strings and identifiers are not replaced,
+// ?? is replaced by a literal ? on every dialect, the statement is built with ? so the numbering does not depend on shared state
+func rebind(d sqlDialect, query string) string {
+	if !strings.Contains(query, "?") || d.BindVar(1) == "?" && !strings.Contains(query, "??") {
+		return query
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(query) + 16)
+
+	var quote byte
+	n := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?' && i+1 < len(query) && query[i+1] == '?':
+			// ?? is the escaped ? operator, such as the jsonb operators ?, ?| and ?& of postgres
+			i++
+		case c == '?':
+			n++
+			sb.WriteString(d.BindVar(n))
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// bindVars returns n ? bind vars separated by comma
+func bindVars(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// expandIn expands the slice args of the query, for example "id in(?)" with []int{1, 2} is "id in(?, ?)",
+// the ? in quoted strings and identifiers and the escaped ?? are not bind vars, as rebind does,
+// the query and args are returned unchanged if they cannot be expanded
+func expandIn(query string, args []interface{}) (string, []interface{}) {
+	if len(args) == 0 {
+		return query, args
+	}
+
+	var sb strings.Builder
+	expanded := make([]interface{}, 0, len(args))
+
+	var quote byte
+	n := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?' && i+1 < len(query) && query[i+1] == '?':
+			sb.WriteString("??")
+			i++
+			continue
+		case c == '?':
+			if n == len(args) {
+				return query, args
+			}
+			arg := args[n]
+			n++
+
+			v, ok := sliceArg(arg)
+			if !ok {
+				expanded = append(expanded, arg)
+				break
+			}
+			if v.Len() == 0 {
+				return query, args
+			}
+			for j := 0; j < v.Len(); j++ {
+				if j > 0 {
+					sb.WriteString(", ")
+				}
+				sb.WriteByte('?')
+				expanded = append(expanded, v.Index(j).Interface())
+			}
+			continue
+		}
+		sb.WriteByte(c)
+	}
+
+	if n != len(args) {
+		return query, args
+	}
+	return sb.String(), expanded
+}
+
+// sliceArg returns the slice of the arg that is expanded by expandIn, []byte and driver.Valuer are single values
+func sliceArg(arg interface{}) (reflect.Value, bool) {
+	if arg == nil {
+		return reflect.Value{}, false
+	}
+	if _, ok := arg.(driver.Valuer); ok {
+		return reflect.Value{}, false
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(arg))
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() == reflect.Uint8 {
+		return reflect.Value{}, false
+	}
+	return v, true
+}
+
+// join add join clause, kind is JOIN, LEFT JOIN, RIGHT JOIN or INNER JOIN
+func (s *SQLBuilder) join(kind string, table string, on string, args ...interface{}) {
+	on, args = expandIn(on, args)
+	s.joins = append(s.joins, fmt.Sprintf("%s %s ON %s", kind, s.quoteTable(table), on))
+	s.joinArgs = append(s.joinArgs, args...)
+}
+
+// Having for example Having("count(*) > ?", 1)
+func (s *SQLBuilder) Having(str string, args ...interface{}) {
+	str, args = expandIn(str, args)
+	if s.having != "" {
+		s.having = fmt.Sprintf("%s AND (%s)", s.having, str)
+	} else {
+		s.having = fmt.Sprintf("HAVING (%s)", str)
+	}
+	s.havingArgs = append(s.havingArgs, args...)
+}
+
+// condition converts the where argument to SQL, query can be a string or Condition
+func (s *SQLBuilder) condition(query interface{}, args []interface{}) (string, []interface{}) {
+	switch q := query.(type) {
+	case string:
+		return expandIn(q, args)
+	case Condition:
+		str, args := q.Build(s.dialect)
+		return expandIn(str, args)
+	}
+
+	log.Panicf("where argument must be string or gosql.Condition, but get %#v", query)
+	return "", nil
+}
+
+func (s *SQLBuilder) Where(query interface{}, args ...interface{}) {
+	str, args := s.condition(query, args)
+	if str == "" {
+		return
+	}
+
 	if s.where != "" {
 		s.where = fmt.Sprintf("%s AND (%s)", s.where, str)
 	} else {
@@ -127,3 +569,49 @@ func (s *SQLBuilder) Where(str string, args ...interface{}) {
 		}
 	}
 }
+
+// scope adds the condition to the whole where clause, the where clause with OR is wrapped in parentheses
+func (s *SQLBuilder) scope(query interface{}, args ...interface{}) {
+	if hasTopLevelOr(s.where) {
+		s.where = "WHERE (" + strings.TrimPrefix(s.where, "WHERE ") + ")"
+	}
+	s.Where(query, args...)
+}
+
+// hasTopLevelOr reports whether the where clause has an OR outside parentheses and quoted text
+func hasTopLevelOr(where string) bool {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(where); i++ {
+		c := where[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && strings.HasPrefix(where[i:], " OR "):
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SQLBuilder) OrWhere(query interface{}, args ...interface{}) {
+	str, args := s.condition(query, args)
+	if str == "" {
+		return
+	}
+
+	if s.where != "" {
+		s.where = fmt.Sprintf("%s OR (%s)", s.where, str)
+	} else {
+		s.where = fmt.Sprintf("WHERE (%s)", str)
+	}
+	s.args = append(s.args, args...)
+}
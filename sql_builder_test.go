@@ -2,6 +2,7 @@ package gosql
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 )
 
@@ -62,6 +63,26 @@ func TestSQLBuilder_insertString(t *testing.T) {
 	}
 }
 
+func TestSQLBuilder_insertRowsString(t *testing.T) {
+	b := &SQLBuilder{
+		dialect: mustGetDialect("mysql"),
+		table:   "users",
+	}
+
+	query := b.insertRowsString([]string{"name", "status"}, []map[string]interface{}{
+		{"name": "test1", "status": 1},
+		{"name": "test2", "status": 2},
+	})
+
+	if query != "INSERT INTO `users` (`name`,`status`) VALUES(?,?),(?,?);" {
+		t.Error("sql builder insert rows error", query)
+	}
+
+	if args := fmt.Sprint(b.args); args != "[test1 1 test2 2]" {
+		t.Error("sql builder insert rows args error", args)
+	}
+}
+
 func TestSQLBuilder_updateString(t *testing.T) {
 
 	b := &SQLBuilder{
@@ -139,3 +160,425 @@ func TestSQLBuilder_Dialect(t *testing.T) {
 		}
 	}
 }
+
+func TestSQLBuilder_joinString(t *testing.T) {
+	b := &SQLBuilder{
+		dialect: mustGetDialect("mysql"),
+		table:   "moments",
+	}
+
+	b.Where("moments.status = ?", 1)
+	b.join("LEFT JOIN", "users u", "u.id = moments.user_id and u.status = ?", 2)
+	b.join("JOIN", "photos AS p", "p.moment_id = moments.id")
+
+	if q := b.queryString(); q != "SELECT `moments`.* FROM `moments` LEFT JOIN `users` AS `u` ON u.id = moments.user_id and u.status = ? JOIN `photos` AS `p` ON p.moment_id = moments.id WHERE (moments.status = ?);" {
+		t.Error("sql builder join query error", q)
+	}
+
+	if q := b.countString(); q != "SELECT count(*) FROM `moments` LEFT JOIN `users` AS `u` ON u.id = moments.user_id and u.status = ? JOIN `photos` AS `p` ON p.moment_id = moments.id WHERE (moments.status = ?);" {
+		t.Error("sql builder join count error", q)
+	}
+
+	if args := fmt.Sprint(b.queryArgs()); args != "[2 1]" {
+		t.Error("sql builder join args error", args)
+	}
+}
+
+func TestSQLBuilder_quoteTable(t *testing.T) {
+	tests := map[string]string{
+		"users":         `"users"`,
+		"users u":       `"users" AS "u"`,
+		"users as u":    `"users" AS "u"`,
+		"public.users":  `"public"."users"`,
+		"(select 1) t1": "(select 1) t1",
+	}
+
+	b := &SQLBuilder{dialect: mustGetDialect("postgres")}
+	for table, want := range tests {
+		if got := b.quoteTable(table); got != want {
+			t.Errorf("quoteTable(%s) = %s, want %s", table, got, want)
+		}
+	}
+}
+
+func TestSQLBuilder_groupString(t *testing.T) {
+	b := &SQLBuilder{
+		dialect: mustGetDialect("mysql"),
+		table:   "moments",
+		fields:  "user_id, count(*) as total",
+		groupBy: "user_id",
+		order:   "total desc",
+		limit:   "10",
+	}
+
+	b.Having("count(*) > ?", 5)
+	b.Where("status = ?", 1)
+
+	if q := b.queryString(); q != "SELECT user_id, count(*) as total FROM `moments` WHERE (status = ?) GROUP BY user_id HAVING (count(*) > ?) ORDER BY total desc LIMIT 10;" {
+		t.Error("sql builder group query error", q)
+	}
+
+	if q := b.countString(); q != "SELECT count(*) FROM (SELECT 1 FROM `moments` WHERE (status = ?) GROUP BY user_id HAVING (count(*) > ?)) AS t;" {
+		t.Error("sql builder group count error", q)
+	}
+
+	if args := fmt.Sprint(b.queryArgs()); args != "[1 5]" {
+		t.Error("sql builder having args error", args)
+	}
+}
+
+func TestSQLBuilder_distinctString(t *testing.T) {
+	b := &SQLBuilder{
+		dialect:  mustGetDialect("mysql"),
+		table:    "moments",
+		fields:   "user_id",
+		distinct: true,
+	}
+
+	if q := b.queryString(); q != "SELECT DISTINCT user_id FROM `moments`;" {
+		t.Error("sql builder distinct query error", q)
+	}
+
+	if q := b.countString(); q != "SELECT count(*) FROM (SELECT DISTINCT user_id FROM `moments`) AS t;" {
+		t.Error("sql builder distinct count error", q)
+	}
+}
+
+func TestSQLBuilder_upsertString(t *testing.T) {
+	testData := map[string]string{
+		"mysql":    "INSERT INTO `users` (`id`,`login_total`,`name`) VALUES(?,?,?) ON DUPLICATE KEY UPDATE `login_total`=users.login_total + 1,`name`=VALUES(`name`),`status`=?;",
+		"postgres": `INSERT INTO "users" ("id","login_total","name") VALUES($1,$2,$3) ON CONFLICT ("id") DO UPDATE SET "login_total"=users.login_total + 1,"name"=EXCLUDED."name","status"=$4;`,
+		"sqlite3":  `INSERT INTO "users" ("id","login_total","name") VALUES(?,?,?) ON CONFLICT ("id") DO UPDATE SET "login_total"=users.login_total + 1,"name"=EXCLUDED."name","status"=?;`,
+	}
+
+	for k, v := range testData {
+		b := &SQLBuilder{
+			dialect: mustGetDialect(k),
+			table:   "users",
+		}
+
+		update := b.excludedUpdates([]string{"name"})
+		update["login_total"] = Expr("users.login_total + 1")
+		update["status"] = 2
+
+		query := b.upsertString(map[string]interface{}{
+			"id":          1,
+			"name":        "test",
+			"login_total": 1,
+		}, []string{"id"}, update)
+
+		if query != v {
+			t.Error(fmt.Sprintf("sql builder %s dialect upsert error", k), query)
+		}
+
+		if args := fmt.Sprint(b.args); args != "[1 1 test 2]" {
+			t.Error(fmt.Sprintf("sql builder %s dialect upsert args error", k), args)
+		}
+	}
+}
+
+func TestSQLBuilder_upsertError(t *testing.T) {
+	if err := (&SQLBuilder{dialect: mustGetDialect("mysql")}).upsertError(nil); err != nil {
+		t.Error("mysql upsert does not need the conflict columns", err)
+	}
+
+	for _, k := range []string{"postgres", "sqlite3"} {
+		b := &SQLBuilder{dialect: mustGetDialect(k)}
+		if err := b.upsertError(nil); err == nil {
+			t.Error(fmt.Sprintf("%s upsert must need the conflict columns", k))
+		}
+
+		if err := b.upsertError([]string{"id"}); err != nil {
+			t.Error(fmt.Sprintf("%s upsert error", k), err)
+		}
+	}
+}
+
+func TestSQLBuilder_upsertNothingString(t *testing.T) {
+	testData := map[string]string{
+		"mysql":   "INSERT INTO `users` (`id`,`name`) VALUES(?,?) ON DUPLICATE KEY UPDATE `id`=`id`;",
+		"sqlite3": `INSERT INTO "users" ("id","name") VALUES(?,?) ON CONFLICT ("id") DO NOTHING;`,
+	}
+
+	for k, v := range testData {
+		b := &SQLBuilder{
+			dialect: mustGetDialect(k),
+			table:   "users",
+		}
+
+		query := b.upsertString(map[string]interface{}{"id": 1, "name": "test"}, []string{"id"}, nil)
+		if query != v {
+			t.Error(fmt.Sprintf("sql builder %s dialect upsert nothing error", k), query)
+		}
+	}
+}
+
+func TestSQLBuilder_postgresBindVars(t *testing.T) {
+	b := &SQLBuilder{
+		dialect: mustGetDialect("postgres"),
+		table:   "users u",
+	}
+
+	b.join("JOIN", "moments m", "m.user_id = u.id and m.status = ?", 1)
+	b.Where("u.id in(?)", []int{1, 2, 3})
+	b.Where("u.name <> '?'")
+	b.Where(Or(Eq("u.status", 1), Between("u.created_at", "2018-01-01", "2019-01-01")))
+	b.Having("count(*) > ?", 1)
+	b.groupBy = "u.id"
+
+	want := `SELECT "u".* FROM "users" AS "u" JOIN "moments" AS "m" ON m.user_id = u.id and m.status = $1 WHERE (u.id in($2, $3, $4)) AND (u.name <> '?') AND (("u"."status" = $5) OR ("u"."created_at" BETWEEN $6 AND $7)) GROUP BY u.id HAVING (count(*) > $8);`
+	for i := 0; i < 2; i++ {
+		if query := b.queryString(); query != want {
+			t.Error("sql builder postgres bind vars error", query)
+		}
+	}
+
+	if args := fmt.Sprint(b.queryArgs()); args != "[1 1 2 3 1 2018-01-01 2019-01-01 1]" {
+		t.Error("sql builder postgres args error", args)
+	}
+
+	u := &SQLBuilder{
+		dialect: mustGetDialect("postgres"),
+		table:   "users",
+	}
+	u.Where("id = ?", 1)
+	query := u.updateString(map[string]interface{}{
+		"name":       "test",
+		"like_total": Expr("like_total + ?", 1),
+	})
+
+	if query != `UPDATE "users" SET "like_total"=like_total + $1,"name"=$2 WHERE (id = $3);` {
+		t.Error("sql builder postgres update error", query)
+	}
+
+	j := &SQLBuilder{
+		dialect: mustGetDialect("postgres"),
+		table:   "users",
+	}
+	j.Where("tags ?? ?", "a")
+	j.Where("tags ??| array['b'] and id = ?", 1)
+
+	if query := j.queryString(); query != `SELECT * FROM "users" WHERE (tags ? $1) AND (tags ?| array['b'] and id = $2);` {
+		t.Error("sql builder postgres escaped operator error", query)
+	}
+
+	j.Where("tags ?? ? and id in (?)", "c", []int{1, 2})
+	if query := j.queryString(); query != `SELECT * FROM "users" WHERE (tags ? $1) AND (tags ?| array['b'] and id = $2) AND (tags ? $3 and id in ($4, $5));` {
+		t.Error("sql builder postgres escaped operator with in error", query)
+	}
+
+	if args := fmt.Sprint(j.queryArgs()); args != "[a 1 c 1 2]" {
+		t.Error("sql builder postgres escaped operator args error", args)
+	}
+
+	if query := rebind(mustGetDialect("mysql"), "SELECT '??' FROM `users` WHERE name ?? ?"); query != "SELECT '??' FROM `users` WHERE name ? ?" {
+		t.Error("mysql must unescape ?? too", query)
+	}
+}
+
+func Test_expandIn(t *testing.T) {
+	query, args := expandIn("id in(?) and name = ? and data = ?", []interface{}{[]int{1, 2}, "test", []byte("a")})
+	if query != "id in(?, ?) and name = ? and data = ?" || fmt.Sprint(args) != "[1 2 test [97]]" {
+		t.Error("expand in error", query, args)
+	}
+
+	query, args = expandIn("name = '?' and tags ?? ? and id in(?)", []interface{}{"a", []int{1, 2}})
+	if query != "name = '?' and tags ?? ? and id in(?, ?)" || fmt.Sprint(args) != "[a 1 2]" {
+		t.Error("expand in with escaped ? error", query, args)
+	}
+
+	query, args = expandIn("id in(?)", []interface{}{[]int{}})
+	if query != "id in(?)" || len(args) != 1 {
+		t.Error("empty slice must not be expanded", query, args)
+	}
+}
+
+func TestSQLBuilder_postgresConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	errs := make(chan string, 100)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			b := &SQLBuilder{
+				dialect: mustGetDialect("postgres"),
+				table:   "users",
+			}
+			b.Where("id = ? and status = ?", i, 1)
+
+			if query := b.queryString(); query != `SELECT * FROM "users" WHERE (id = $1 and status = $2);` {
+				errs <- query
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for query := range errs {
+		t.Error("sql builder postgres concurrent error", query)
+	}
+}
+
+func TestSQLBuilder_hintString(t *testing.T) {
+	testData := map[string]string{
+		"mysql":      "/*+TDDL:slave()*/SELECT /*+ MAX_EXECUTION_TIME(1000) */ * FROM `users` force index(idx_status) WHERE (status = ?);",
+		"postgres":   `/*+TDDL:slave()*//*+ MAX_EXECUTION_TIME(1000) */ SELECT * FROM "users" WHERE (status = $1);`,
+		"sqlite3":    `/*+TDDL:slave()*/SELECT * FROM "users" INDEXED BY idx_status WHERE (status = ?);`,
+		"mssql":      "/*+TDDL:slave()*/SELECT * FROM [users] WITH (INDEX(idx_status)) WHERE (status = @p1) OPTION (MAX_EXECUTION_TIME(1000));",
+		"clickhouse": "/*+TDDL:slave()*/SELECT * FROM `users` WHERE (status = ?) SETTINGS MAX_EXECUTION_TIME(1000)",
+	}
+
+	for k, v := range testData {
+		b := &SQLBuilder{
+			dialect:       mustGetDialect(k),
+			table:         "users",
+			hint:          "/*+TDDL:slave()*/",
+			forceIndex:    "idx_status",
+			optimizerHint: "MAX_EXECUTION_TIME(1000)",
+		}
+		b.Where("status = ?", 1)
+
+		if query := b.queryString(); query != v {
+			t.Error(fmt.Sprintf("sql builder %s dialect hint error", k), query)
+		}
+	}
+}
+
+func TestSQLBuilder_lockString(t *testing.T) {
+	testData := map[string]string{
+		"mysql":    "SELECT * FROM `jobs` WHERE (status = ?) ORDER BY id LIMIT 10 FOR UPDATE SKIP LOCKED;",
+		"postgres": `SELECT * FROM "jobs" WHERE (status = $1) ORDER BY id LIMIT 10 FOR UPDATE SKIP LOCKED;`,
+		"sqlite3":  `SELECT * FROM "jobs" WHERE (status = ?) ORDER BY id LIMIT 10;`,
+	}
+
+	for k, v := range testData {
+		b := &SQLBuilder{
+			dialect:    mustGetDialect(k),
+			table:      "jobs",
+			order:      "id",
+			limit:      "10",
+			lock:       "UPDATE",
+			lockOption: "SKIP LOCKED",
+		}
+		b.Where("status = ?", 0)
+
+		if query := b.queryString(); query != v {
+			t.Error(fmt.Sprintf("sql builder %s dialect lock error", k), query)
+		}
+	}
+
+	b := &SQLBuilder{
+		dialect: mustGetDialect("postgres"),
+		table:   "jobs",
+		lock:    "SHARE",
+	}
+	if query := b.queryString(); query != `SELECT * FROM "jobs" FOR SHARE;` {
+		t.Error("sql builder share lock error", query)
+	}
+
+	if query := b.countString(); query != `SELECT count(*) FROM "jobs";` {
+		t.Error("sql builder count must not lock", query)
+	}
+}
+
+func TestSQLBuilder_aggregateString(t *testing.T) {
+	b := &SQLBuilder{
+		dialect: mustGetDialect("mysql"),
+		table:   "moments",
+		order:   "id desc",
+		limit:   "10",
+	}
+	b.Where("user_id = ?", 5)
+
+	if query := b.aggregateString("SUM", "like_total"); query != "SELECT SUM(`like_total`) FROM `moments` WHERE (user_id = ?);" {
+		t.Error("sql builder aggregate error", query)
+	}
+
+	b.groupBy = "user_id"
+	if query := b.aggregateString("MAX", "m.like_total"); query != "SELECT MAX(t.aggregate) FROM (SELECT MAX(`m`.`like_total`) AS aggregate FROM `moments` WHERE (user_id = ?) GROUP BY user_id) AS t;" {
+		t.Error("sql builder grouped aggregate error", query)
+	}
+
+	if query := b.aggregateString("AVG", "like_total"); query != "SELECT SUM(t.total) * 1.0 / NULLIF(SUM(t.num), 0) FROM (SELECT SUM(`like_total`) AS total, COUNT(`like_total`) AS num FROM `moments` WHERE (user_id = ?) GROUP BY user_id) AS t;" {
+		t.Error("sql builder grouped average error", query)
+	}
+
+	b.groupBy, b.distinct = "", true
+	if query := b.aggregateString("SUM", "like_total"); query != "SELECT SUM(DISTINCT `like_total`) FROM `moments` WHERE (user_id = ?);" {
+		t.Error("sql builder distinct aggregate error", query)
+	}
+}
+
+func TestSQLBuilder_pluckString(t *testing.T) {
+	b := &SQLBuilder{
+		dialect: mustGetDialect("postgres"),
+		table:   "users",
+		order:   "id desc",
+		limit:   "10",
+	}
+	b.Where("status = ?", 1)
+
+	if query := b.pluckString("name"); query != `SELECT "name" FROM "users" WHERE (status = $1) ORDER BY id desc LIMIT 10;` {
+		t.Error("sql builder pluck error", query)
+	}
+
+	b.distinct = true
+	if query := b.pluckString("name"); query != `SELECT DISTINCT "name" FROM "users" WHERE (status = $1) ORDER BY id desc LIMIT 10;` {
+		t.Error("sql builder distinct pluck error", query)
+	}
+}
+
+func TestSQLBuilder_existsString(t *testing.T) {
+	testData := map[string]string{
+		"mysql": "SELECT 1 FROM `users` WHERE (status = ?) LIMIT 1;",
+		"mssql": "SELECT 1 FROM [users] WHERE (status = @p1) ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 1 ROWS ONLY;",
+	}
+
+	for k, v := range testData {
+		b := &SQLBuilder{
+			dialect: mustGetDialect(k),
+			table:   "users",
+			order:   "id desc",
+		}
+		b.Where("status = ?", 1)
+
+		if query := b.existsString(); query != v {
+			t.Error(fmt.Sprintf("sql builder %s dialect exists error", k), query)
+		}
+	}
+}
+
+func TestSQLBuilder_updateReturningString(t *testing.T) {
+	b := &SQLBuilder{
+		dialect: mustGetDialect("postgres"),
+		table:   "moments",
+	}
+	b.Where("id = ?", 1)
+
+	query := b.updateReturningString(map[string]interface{}{"like_total": Expr(`"like_total" + ?`, 1)}, []string{"like_total"})
+	if query != `UPDATE "moments" SET "like_total"="like_total" + $1 WHERE (id = $2) RETURNING "like_total";` {
+		t.Error("sql builder update returning error", query)
+	}
+}
+
+func TestSQLBuilder_insertRowsReturningString(t *testing.T) {
+	testData := map[string]string{
+		"postgres": `INSERT INTO "users" ("name","status") VALUES($1,$2),($3,$4) RETURNING "id";`,
+		"mssql":    `INSERT INTO [users] ([name],[status]) OUTPUT INSERTED.[id] VALUES(@p1,@p2),(@p3,@p4);`,
+	}
+
+	for k, v := range testData {
+		b := &SQLBuilder{
+			dialect: mustGetDialect(k),
+			table:   "users",
+		}
+		rows := []map[string]interface{}{
+			{"name": "a", "status": 1},
+			{"name": "b", "status": 2},
+		}
+
+		if query := b.insertRowsReturningString([]string{"name", "status"}, rows, []string{"id"}); query != v {
+			t.Error(fmt.Sprintf("sql builder %s dialect insert rows returning error", k), query)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package gosql
+
+import (
+	"reflect"
+)
+
+// Tracker is embedded in the model to track the changes since the model was loaded by Get or All,
+// Update of the loaded model only writes the changed columns, including the columns changed to zero values
+//
+//	type Users struct {
+//	    gosql.Tracker
+//	    Id   int    `db:"id"`
+//	    Name string `db:"name"`
+//	}
+type Tracker struct {
+	snapshot map[string]interface{}
+}
+
+func (t *Tracker) tracker() *Tracker {
+	return t
+}
+
+// tracked is implemented by the model that embeds Tracker
+type tracked interface {
+	tracker() *Tracker
+}
+
+// Change is the value of the column when the model was loaded and its current value
+type Change struct {
+	Old interface{}
+	New interface{}
+}
+
+// Changes returns the changed columns of the model since it was loaded by Get or All,
+// it is nil if the model does not embed Tracker or has not been loaded
+func Changes(model interface{}) map[string]Change {
+	t, ok := model.(tracked)
+	if !ok || t.tracker().snapshot == nil {
+		return nil
+	}
+
+	changes := make(map[string]Change)
+	for k, v := range mapper.FieldMap(reflect.ValueOf(model)) {
+		old, current := t.tracker().snapshot[k], reflect.Indirect(v).Interface()
+		if !reflect.DeepEqual(old, current) {
+			changes[k] = Change{Old: old, New: current}
+		}
+	}
+	return changes
+}
+
+// snapshot saves the values of the tracked model, the slice of models is saved one by one
+func snapshot(model interface{}) {
+	v := reflect.Indirect(reflect.ValueOf(model))
+	if v.Kind() == reflect.Interface {
+		v = reflect.Indirect(v.Elem())
+	}
+	if v.Kind() == reflect.Slice {
+		for i := 0; i < v.Len(); i++ {
+			item := v.Index(i)
+			if item.Kind() != reflect.Ptr && item.CanAddr() {
+				item = item.Addr()
+			}
+			snapshot(item.Interface())
+		}
+		return
+	}
+
+	t, ok := model.(tracked)
+	if !ok {
+		return
+	}
+
+	values := make(map[string]interface{})
+	for k, f := range mapper.FieldMap(reflect.ValueOf(model)) {
+		f = reflect.Indirect(f)
+		// the bytes are copied, otherwise the snapshot changes with the field
+		if b, ok := f.Interface().([]byte); ok && b != nil {
+			values[k] = append([]byte{}, b...)
+			continue
+		}
+		values[k] = f.Interface()
+	}
+	t.tracker().snapshot = values
+}
+
+// changedValues the changed columns of the tracked model, the primary key and the zeroValues columns are always included,
+// ok is false if the model is not tracked
+func changedValues(model interface{}, pk string, fields map[string]reflect.Value, zeroValues []string) (m map[string]interface{}, ok bool) {
+	t, ok := model.(tracked)
+	if !ok || t.tracker().snapshot == nil {
+		return nil, false
+	}
+
+	m = make(map[string]interface{})
+	for k, v := range fields {
+		v = reflect.Indirect(v)
+		if k == pk || inSlice(k, zeroValues) || !reflect.DeepEqual(t.tracker().snapshot[k], v.Interface()) {
+			m[k] = v.Interface()
+		}
+	}
+	return m, true
+}
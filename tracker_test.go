@@ -0,0 +1,116 @@
+package gosql
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type trackedPost struct {
+	Tracker
+	Id        int       `db:"id"`
+	Title     string    `db:"title"`
+	Version   uint      `db:"version"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (p *trackedPost) TableName() string {
+	return "posts"
+}
+
+func (p *trackedPost) PK() string {
+	return "id"
+}
+
+func TestChanges(t *testing.T) {
+	post := &trackedPost{Id: 1, Title: "test", Version: 2}
+	if Changes(post) != nil {
+		t.Error("the model not loaded must have no changes")
+	}
+
+	if _, ok := mapper.FieldMap(reflect.ValueOf(post))["snapshot"]; ok {
+		t.Error("tracker must not be mapped")
+	}
+
+	snapshot(post)
+	if changes := Changes(post); len(changes) != 0 {
+		t.Error("changes error", changes)
+	}
+
+	post.Title = "hello"
+	post.Version = 0
+	changes := Changes(post)
+	if !reflect.DeepEqual(changes, map[string]Change{
+		"title":   {Old: "test", New: "hello"},
+		"version": {Old: uint(2), New: uint(0)},
+	}) {
+		t.Error("changes error", changes)
+	}
+
+	m, ok := changedValues(post, "id", mapper.FieldMap(reflect.ValueOf(post)), []string{"created_at"})
+	if !ok || !reflect.DeepEqual(m, map[string]interface{}{
+		"id":         1,
+		"title":      "hello",
+		"version":    uint(0),
+		"created_at": time.Time{},
+	}) {
+		t.Error("changed values error", m)
+	}
+
+	if Changes(&softPost{}) != nil {
+		t.Error("the model without tracker must have no changes")
+	}
+}
+
+func Test_snapshotSlice(t *testing.T) {
+	posts := []trackedPost{{Id: 1}, {Id: 2}}
+	snapshot(&posts)
+	for _, post := range posts {
+		if post.snapshot == nil {
+			t.Error("snapshot of slice error")
+		}
+	}
+
+	ptrs := []*trackedPost{{Id: 1}}
+	var m interface{} = &ptrs
+	snapshot(&m)
+	if ptrs[0].snapshot == nil {
+		t.Error("snapshot of pointer slice error")
+	}
+}
+
+func TestBuilder_UpdateTracked(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		if _, err := Model(&trackedPost{Title: "test", Version: 3}).Create(); err != nil {
+			t.Fatal(err)
+		}
+
+		post := &trackedPost{Id: 1}
+		if err := Model(post).Get(); err != nil {
+			t.Fatal(err)
+		}
+
+		post.Version = 0
+		if _, err := Model(post).Update(); err != nil {
+			t.Fatal(err)
+		}
+
+		if changes := Changes(post); len(changes) != 0 {
+			t.Error("snapshot must be taken after update", changes)
+		}
+
+		if affected, err := Model(post).Update(); err != nil || affected != 0 {
+			t.Error("unchanged model must not be updated", affected, err)
+		}
+
+		result := &trackedPost{Id: 1}
+		if err := Model(result).Get(); err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Version != 0 || result.Title != "test" {
+			t.Errorf("zero value must be written: %#v", result)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package gosql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// TxOptions is the options of a transaction
+type TxOptions struct {
+	// Isolation is the transaction isolation level, zero is the driver's default level
+	Isolation sql.IsolationLevel
+	// ReadOnly begins a read only transaction
+	ReadOnly bool
+	// Retry re-runs the whole transaction on a retryable error, nil means no retry
+	Retry *RetryPolicy
+}
+
+// RetryPolicy decides how a transaction is retried
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of runs, including the first one
+	MaxAttempts int
+	// Backoff returns the wait before the retry attempt, attempt starts from 1
+	Backoff func(attempt int) time.Duration
+	// Retryable reports whether err can be retried, default is the Dialect classifier,
+	// such as MySQL deadlock 1213 and lock wait timeout 1205, Postgres serialization failure 40001
+	Retryable func(err error) bool
+}
+
+// ExponentialBackoff returns a backoff that doubles from base and does not exceed max
+func ExponentialBackoff(base, max time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < max; i++ {
+			d *= 2
+		}
+
+		if d > max {
+			return max
+		}
+		return d
+	}
+}
+
+func (r *RetryPolicy) retryable(d sqlDialect, err error) bool {
+	if r.Retryable != nil {
+		return r.Retryable(err)
+	}
+	return d.IsRetryable(err)
+}
+
+// wait sleeps for the backoff of the retry attempt, it returns early if ctx is done
+func (r *RetryPolicy) wait(ctx context.Context, attempt int) error {
+	if r.Backoff == nil {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(r.Backoff(attempt))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// TxxWithOptions the transaction with context and options, for example:
+//
+//	gosql.TxxWithOptions(ctx, &gosql.TxOptions{
+//	    Isolation: sql.LevelSerializable,
+//	    Retry:     &gosql.RetryPolicy{MaxAttempts: 3, Backoff: gosql.ExponentialBackoff(10*time.Millisecond, time.Second)},
+//	}, func(ctx context.Context, tx *gosql.DB) error {...})
+//
+// If it is already a transaction, fn runs in a savepoint and the options are ignored
+func (w *DB) TxxWithOptions(ctx context.Context, opts *TxOptions, fn func(ctx context.Context, tx *DB) error) (err error) {
+	if w.tx != nil {
+		return w.nested(ctx, fn)
+	}
+
+	if opts == nil {
+		return w.txx(ctx, nil, fn)
+	}
+
+	txOpts := &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly}
+	for attempt := 1; ; attempt++ {
+		err = w.txx(ctx, txOpts, fn)
+		if err == nil || opts.Retry == nil || attempt >= opts.Retry.MaxAttempts || !opts.Retry.retryable(w.dialect(), err) {
+			return err
+		}
+
+		if werr := opts.Retry.wait(ctx, attempt); werr != nil {
+			return err
+		}
+	}
+}
+
+// TxWithOptions the transaction with options
+func (w *DB) TxWithOptions(opts *TxOptions, fn func(tx *DB) error) error {
+	return w.TxxWithOptions(context.Background(), opts, func(ctx context.Context, tx *DB) error {
+		return fn(tx)
+	})
+}
+
+// TxxWithOptions default database the transaction with context and options
+func TxxWithOptions(ctx context.Context, opts *TxOptions, fn func(ctx context.Context, tx *DB) error) error {
+	return Use(defaultLink).TxxWithOptions(ctx, opts, fn)
+}
+
+// TxWithOptions default database the transaction with options
+func TxWithOptions(opts *TxOptions, fn func(tx *DB) error) error {
+	return Use(defaultLink).TxWithOptions(opts, fn)
+}
@@ -0,0 +1,186 @@
+package gosql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/ilibs/gosql/v2/internal/example/models"
+)
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string {
+	return "pq: " + string(e)
+}
+
+func (e sqlStateError) SQLState() string {
+	return string(e)
+}
+
+func TestDialect_IsRetryable(t *testing.T) {
+	tests := []struct {
+		dialect string
+		err     error
+		want    bool
+	}{
+		{"mysql", &mysql.MySQLError{Number: 1213}, true},
+		{"mysql", fmt.Errorf("create user: %w", &mysql.MySQLError{Number: 1205}), true},
+		{"mysql", &mysql.MySQLError{Number: 1062}, false},
+		{"mysql", errors.New("deadlock"), false},
+		{"postgres", sqlStateError("40001"), true},
+		{"postgres", sqlStateError("40P01"), true},
+		{"postgres", sqlStateError("23505"), false},
+		{"sqlite3", errors.New("database is locked"), true},
+		{"sqlite3", errors.New("no such table"), false},
+	}
+
+	for _, tt := range tests {
+		if got := mustGetDialect(tt.dialect).IsRetryable(tt.err); got != tt.want {
+			t.Errorf("%s IsRetryable(%v) = %v, want %v", tt.dialect, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+	want := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond, 50 * time.Millisecond}
+
+	for i, w := range want {
+		if got := backoff(i + 1); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
+
+func TestTxxWithOptions(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		attempts := 0
+		opts := &TxOptions{
+			Isolation: sql.LevelSerializable,
+			Retry:     &RetryPolicy{MaxAttempts: 3, Backoff: ExponentialBackoff(time.Millisecond, 10*time.Millisecond)},
+		}
+
+		err := TxxWithOptions(context.Background(), opts, func(ctx context.Context, tx *DB) error {
+			attempts++
+			if _, err := tx.Model(&models.Users{Name: "test"}).Create(); err != nil {
+				return err
+			}
+
+			if attempts == 1 {
+				return &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+			}
+			return nil
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if attempts != 2 {
+			t.Error("transaction retry attempts error", attempts)
+		}
+
+		num, _ := Model(&models.Users{}).Count()
+		if num != 1 {
+			t.Error("retried transaction must rollback the failed attempt", num)
+		}
+	})
+}
+
+func TestTxWithOptions_ReadOnly(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		err := TxWithOptions(&TxOptions{ReadOnly: true}, func(tx *DB) error {
+			_, err := tx.Model(&models.Users{Name: "test"}).Create()
+			return err
+		})
+
+		if err == nil {
+			t.Error("read only transaction must not write")
+		}
+	})
+}
+
+type callbackUser struct {
+	models.Users
+	events *[]string
+}
+
+func (u *callbackUser) AfterCreate(tx *DB) {
+	tx.OnCommit(func() {
+		*u.events = append(*u.events, "committed "+u.Name)
+	})
+	tx.OnRollback(func() {
+		*u.events = append(*u.events, "rolled back "+u.Name)
+	})
+}
+
+func TestDB_OnCommitWithoutTx(t *testing.T) {
+	db := &DB{}
+
+	called := false
+	db.OnCommit(func() {
+		called = true
+	})
+	db.OnRollback(func() {
+		t.Error("OnRollback must not run without transaction")
+	})
+
+	if !called {
+		t.Error("OnCommit must run immediately without transaction")
+	}
+}
+
+func TestDB_OnCommit(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		events := make([]string, 0)
+
+		err := Tx(func(tx *DB) error {
+			if _, err := tx.Model(&callbackUser{Users: models.Users{Name: "test1"}, events: &events}).Create(); err != nil {
+				return err
+			}
+
+			_ = tx.Tx(func(tx *DB) error {
+				if _, err := tx.Model(&callbackUser{Users: models.Users{Name: "test2"}, events: &events}).Create(); err != nil {
+					return err
+				}
+				return errors.New("rollback test2")
+			})
+
+			_, err := tx.Model(&callbackUser{Users: models.Users{Name: "test3"}, events: &events}).Create()
+			return err
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := "[rolled back test2 committed test1 committed test3]"
+		if got := fmt.Sprint(events); got != want {
+			t.Errorf("callbacks = %s, want %s", got, want)
+		}
+	})
+}
+
+func TestDB_OnRollback(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		events := make([]string, 0)
+
+		_ = Tx(func(tx *DB) error {
+			if _, err := tx.Model(&callbackUser{Users: models.Users{Name: "test1"}, events: &events}).Create(); err != nil {
+				return err
+			}
+			return errors.New("rollback")
+		})
+
+		want := "[rolled back test1]"
+		if got := fmt.Sprint(events); got != want {
+			t.Errorf("callbacks = %s, want %s", got, want)
+		}
+	})
+}
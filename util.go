@@ -118,6 +118,18 @@ func fillPrimaryKey(v reflect.Value, value int64) {
 	}
 }
 
+// fieldInt returns the value of an integer field, 0 if the field is not an integer
+func fieldInt(v reflect.Value) int64 {
+	v = reflect.Indirect(v)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int64(v.Uint())
+	}
+	return 0
+}
+
 // sortedParamKeys Sorts the param names given - map iteration order is explicitly random in Go
 // but we need params in a defined order to avoid unexpected results.
 func sortedParamKeys(params map[string]interface{}) []string {
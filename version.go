@@ -0,0 +1,59 @@
+package gosql
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrStaleObject is returned by Update when the row has been changed or deleted since the model was read
+var ErrStaleObject = errors.New("stale object, the row has been changed by another update")
+
+// IVersion is implemented by the model that declares its version column by method,
+// the version column can also be declared by the tag `version:"true"`
+type IVersion interface {
+	VersionColumn() string
+}
+
+// versionField returns the version column of the model and its field, the column is empty if there is none
+func (b *Builder) versionField() (string, reflect.Value) {
+	fields := mapper.FieldMap(b.modelReflectValue)
+	if m, ok := b.modelEntity.(IVersion); ok {
+		if v, ok := fields[m.VersionColumn()]; ok {
+			return m.VersionColumn(), v
+		}
+		return "", reflect.Value{}
+	}
+
+	tm := mapper.mapper.TypeMap(reflect.Indirect(b.modelReflectValue).Type())
+	for name, fi := range tm.Names {
+		if fi.Field.Tag.Get("version") != "true" {
+			continue
+		}
+		if v, ok := fields[name]; ok {
+			return name, v
+		}
+	}
+	return "", reflect.Value{}
+}
+
+// versionScope adds the version condition to the where clause and increments the version column
+func (b *Builder) versionScope(column string, field reflect.Value, m map[string]interface{}) {
+	b.scope(Eq(column, fieldInt(field)))
+	m[column] = Expr(b.quoteColumn(column) + " + 1")
+}
+
+// bumpVersion increments the version field of the model after the row is updated
+func bumpVersion(field reflect.Value) {
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		field.SetInt(field.Int() + 1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		field.SetUint(field.Uint() + 1)
+	}
+}
+
+// staleObject returns ErrStaleObject with the table and version of the model
+func staleObject(table string, version int64) error {
+	return fmt.Errorf("%w: %s version %d", ErrStaleObject, table, version)
+}
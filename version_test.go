@@ -0,0 +1,132 @@
+package gosql
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type versionPost struct {
+	Id        int       `db:"id"`
+	Title     string    `db:"title"`
+	Version   uint      `db:"version" version:"true"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (p *versionPost) TableName() string {
+	return "posts"
+}
+
+func (p *versionPost) PK() string {
+	return "id"
+}
+
+type methodVersionPost struct {
+	Id       int    `db:"id"`
+	Revision int64  `db:"revision"`
+	Title    string `db:"title"`
+}
+
+func (p *methodVersionPost) TableName() string {
+	return "posts"
+}
+
+func (p *methodVersionPost) PK() string {
+	return "id"
+}
+
+func (p *methodVersionPost) VersionColumn() string {
+	return "revision"
+}
+
+func TestBuilder_versionScope(t *testing.T) {
+	{
+		post := &versionPost{Id: 1, Title: "test", Version: 3}
+		b := &Builder{
+			modelReflectValue: reflect.ValueOf(post),
+			modelEntity:       post,
+			SQLBuilder: SQLBuilder{
+				dialect: mustGetDialect("mysql"),
+				table:   "posts",
+			},
+		}
+		b.Where("id = ?", 1).OrWhere("title = ?", "test")
+
+		column, field := b.versionField()
+		if column != "version" {
+			t.Fatal("version column error", column)
+		}
+
+		m := map[string]interface{}{"title": "test"}
+		b.versionScope(column, field, m)
+		if query := b.updateString(m); query != "UPDATE `posts` SET `title`=?,`version`=`version` + 1 WHERE ((id = ?) OR (title = ?)) AND (`version` = ?);" {
+			t.Error("version update error", query)
+		}
+		if !reflect.DeepEqual(b.args, []interface{}{"test", 1, "test", int64(3)}) {
+			t.Error("version update args error", b.args)
+		}
+
+		bumpVersion(field)
+		if post.Version != 4 {
+			t.Error("bump version error", post.Version)
+		}
+	}
+
+	{
+		post := &methodVersionPost{Id: 1}
+		b := &Builder{modelReflectValue: reflect.ValueOf(post), modelEntity: post}
+		if column, _ := b.versionField(); column != "revision" {
+			t.Error("version column by method error", column)
+		}
+	}
+
+	{
+		b := &Builder{modelReflectValue: reflect.ValueOf(&softPost{}), modelEntity: &softPost{}}
+		if column, _ := b.versionField(); column != "" {
+			t.Error("model without version column error", column)
+		}
+	}
+}
+
+func TestBuilder_UpdateVersion(t *testing.T) {
+	RunWithSchema(t, func(t *testing.T) {
+		if _, err := Model(&versionPost{Title: "test"}).Create(); err != nil {
+			t.Fatal(err)
+		}
+
+		post1 := &versionPost{Id: 1}
+		if err := Model(post1).Get(); err != nil {
+			t.Fatal(err)
+		}
+
+		post2 := &versionPost{Id: 1}
+		if err := Model(post2).Get(); err != nil {
+			t.Fatal(err)
+		}
+
+		post1.Title = "post1"
+		if _, err := Model(post1).Update(); err != nil {
+			t.Fatal(err)
+		}
+
+		if post1.Version != 1 {
+			t.Error("version must be bumped", post1.Version)
+		}
+
+		post2.Title = "post2"
+		if _, err := Model(post2).Update(); !errors.Is(err, ErrStaleObject) {
+			t.Error("stale object must not be updated", err)
+		}
+
+		post := &versionPost{Id: 1}
+		if err := Model(post).Get(); err != nil {
+			t.Fatal(err)
+		}
+
+		if post.Title != "post1" || post.Version != 1 {
+			t.Errorf("post error: %#v", post)
+		}
+	})
+}